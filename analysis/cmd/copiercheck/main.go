@@ -0,0 +1,15 @@
+// The copiercheck command reports problems at copier.Copy call sites, run it
+// with go vet:
+//
+//	go vet -vettool=$(which copiercheck) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/smw-104/copier/analysis/copiercheck"
+)
+
+func main() {
+	unitchecker.Main(copiercheck.Analyzer)
+}
@@ -0,0 +1,218 @@
+// Package copiercheck defines an Analyzer that reports copier.Copy calls that
+// fail or copy nothing at runtime.
+//
+// It checks that the destination is a pointer, that no copied field is
+// ambiguous, that the source and destination structs have at least one field
+// in common unless the destination has copy hooks, and, for
+// copier.CopyWithOption with a literal copier.Option, the strict options.
+package copiercheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/smw-104/copier/internal/mapping"
+	"github.com/smw-104/copier/internal/mapping/gotypes"
+)
+
+const copierPath = "github.com/smw-104/copier"
+
+// Analyzer reports problems at copier.Copy call sites
+var Analyzer = &analysis.Analyzer{
+	Name:     "copiercheck",
+	Doc:      "report copier.Copy calls that fail or copy nothing",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// options are the copier.Option fields known at a call site
+type options struct {
+	mapping               mapping.Options
+	disallowUnknownSource bool
+	requireAllDest        bool
+
+	// known reports whether the options changing the mapping are known
+	// statically, calls with other options are only checked for unaddressable
+	// destinations
+	known bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != copierPath {
+			return
+		}
+
+		opts := options{known: true}
+		switch fn.Name() {
+		case "Copy":
+		case "CopyWithOption":
+			if len(call.Args) < 3 {
+				return
+			}
+			opts = optionsOf(pass, call.Args[2])
+		default:
+			return
+		}
+
+		if len(call.Args) >= 2 {
+			checkCopy(pass, call, fn, opts)
+		}
+	})
+
+	return nil, nil
+}
+
+// checkCopy reports problems copying the second argument of call to the first
+func checkCopy(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, opts options) {
+	name := fn.Name()
+	toType, fromType := pass.TypesInfo.TypeOf(call.Args[0]), pass.TypesInfo.TypeOf(call.Args[1])
+	if toType == nil || fromType == nil || isInterface(toType) || isInterface(fromType) {
+		// the dynamic types are only known at runtime
+		return
+	}
+
+	if _, ok := toType.Underlying().(*types.Pointer); !ok {
+		pass.Reportf(call.Args[0].Pos(), "copier.%v to non-pointer %v: copy to value is unaddressable", name, typeString(toType))
+		return
+	}
+
+	to, from := mapping.Indirect(gotypes.Of(toType)), mapping.Indirect(gotypes.Of(fromType))
+	if to.Kind() != mapping.Struct || from.Kind() != mapping.Struct || !opts.known {
+		return
+	}
+
+	typeMapping := mapping.Resolve(to, from, opts.mapping)
+
+	if len(typeMapping.AmbiguousSource) > 0 {
+		pass.Reportf(call.Pos(), "copier.%v from %v to %v: ambiguous source fields %v", name, from, to, strings.Join(typeMapping.AmbiguousSource, ", "))
+	}
+	if len(typeMapping.AmbiguousDest) > 0 {
+		pass.Reportf(call.Pos(), "copier.%v from %v to %v: ambiguous destination fields %v", name, from, to, strings.Join(typeMapping.AmbiguousDest, ", "))
+	}
+
+	copied := false
+	for _, field := range typeMapping.Fields {
+		copied = copied || field.Conversion != mapping.ConversionNone
+	}
+	if !copied {
+		// destinations with hooks may get their fields from the hooks alone
+		if !hasCopyHook(fn.Pkg(), toType) {
+			pass.Reportf(call.Pos(), "copier.%v from %v to %v copies no fields", name, from, to)
+		}
+		return
+	}
+
+	if opts.disallowUnknownSource && len(typeMapping.UnknownSource) > 0 {
+		pass.Reportf(call.Pos(), "copier.%v from %v to %v: unknown source fields %v", name, from, to,
+			mapping.DescribeNames(typeMapping.UnknownSource, typeMapping.Suggestions))
+	}
+	if opts.requireAllDest && len(typeMapping.MissingDest) > 0 {
+		pass.Reportf(call.Pos(), "copier.%v from %v to %v: missing destination fields %v", name, from, to,
+			mapping.DescribeNames(typeMapping.MissingDest, typeMapping.Suggestions))
+	}
+}
+
+// optionsOf returns the options set by a copier.Option composite literal of
+// constants, other expressions are only known at runtime
+func optionsOf(pass *analysis.Pass, expr ast.Expr) options {
+	var opts options
+
+	literal, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return opts
+	}
+
+	for _, elt := range literal.Elts {
+		keyValue, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return opts
+		}
+		key, ok := keyValue.Key.(*ast.Ident)
+		if !ok {
+			return opts
+		}
+
+		value := pass.TypesInfo.Types[keyValue.Value].Value
+		switch key.Name {
+		case "TagMatch", "GetterPrefix", "SetterPrefix":
+			if value == nil || value.Kind() != constant.String {
+				return opts
+			}
+		case "Unflatten", "DisallowUnknownSource", "RequireAllDest":
+			if value == nil || value.Kind() != constant.Bool {
+				return opts
+			}
+		default:
+			// options like OnField don't change the mapping
+			continue
+		}
+
+		switch key.Name {
+		case "TagMatch":
+			opts.mapping.TagMatch = constant.StringVal(value)
+		case "GetterPrefix":
+			opts.mapping.GetterPrefix = constant.StringVal(value)
+		case "SetterPrefix":
+			opts.mapping.SetterPrefix = constant.StringVal(value)
+		case "Unflatten":
+			opts.mapping.Unflatten = constant.BoolVal(value)
+		case "DisallowUnknownSource":
+			opts.disallowUnknownSource = constant.BoolVal(value)
+		case "RequireAllDest":
+			opts.requireAllDest = constant.BoolVal(value)
+		}
+	}
+	opts.known = true
+	return opts
+}
+
+// hasCopyHook reports whether a pointer to the destination struct of t
+// implements the copier.BeforeCopier or copier.AfterCopier hooks
+func hasCopyHook(copier *types.Package, t types.Type) bool {
+	t = indirect(t)
+	for _, name := range []string{"BeforeCopier", "AfterCopier"} {
+		hook, ok := copier.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if iface, ok := hook.Type().Underlying().(*types.Interface); ok && types.Implements(types.NewPointer(t), iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// indirect returns the element type of pointers and slices, like mapping.Indirect
+func indirect(t types.Type) types.Type {
+	for {
+		switch underlying := t.Underlying().(type) {
+		case *types.Pointer:
+			t = underlying.Elem()
+		case *types.Slice:
+			t = underlying.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+func isInterface(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Interface)
+	return ok
+}
+
+func typeString(t types.Type) string {
+	return gotypes.Of(t).String()
+}
@@ -0,0 +1,13 @@
+package copiercheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/smw-104/copier/analysis/copiercheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), copiercheck.Analyzer, "a")
+}
@@ -0,0 +1,167 @@
+package a
+
+import "github.com/smw-104/copier"
+
+type User struct {
+	Name    string
+	Role    string
+	Age     int32
+	Employe int64
+}
+
+func (user *User) DoubleAge() int32 {
+	return 2 * user.Age
+}
+
+type Employee struct {
+	Name      string
+	Age       int64
+	DoubleAge int32
+	EmployeId int64
+	SuperRule string
+}
+
+func (employee *Employee) Role(role string) {
+	employee.SuperRule = "Super " + role
+}
+
+type Order struct {
+	ID     int64
+	Status string
+}
+
+type Audit struct {
+	ID   int64
+	Name string
+}
+
+type Owner struct {
+	ID int64
+}
+
+type AuditedOrder struct {
+	Audit
+	Owner
+	Status string
+}
+
+type Contact struct {
+	email string
+}
+
+func (contact *Contact) GetEmail() string {
+	return contact.email
+}
+
+func (contact *Contact) SetEmail(email string) {
+	contact.email = email
+}
+
+type Card struct {
+	contact Contact
+}
+
+func (card *Card) Email() string {
+	return card.contact.email
+}
+
+type ContactDTO struct {
+	Email string
+}
+
+type Common struct {
+	ID int64
+}
+
+type Left struct {
+	Common
+}
+
+type Right struct {
+	Common
+}
+
+type DiamondOrder struct {
+	Left
+	Right
+	Status string
+}
+
+type UserDTO struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type Account struct {
+	ID   int64  `json:"user_id"`
+	Nick string `json:"name"`
+}
+
+type Greeting struct {
+	Text string
+}
+
+func (greeting *Greeting) AfterCopy(from interface{}) error {
+	if user, ok := from.(*User); ok {
+		greeting.Text = "Hello " + user.Name
+	}
+	return nil
+}
+
+type Stamp struct {
+	Seen bool
+}
+
+func (stamp *Stamp) BeforeCopy(from interface{}) error {
+	stamp.Seen = true
+	return nil
+}
+
+func copies(user User, users []User, value interface{}, tag string, strict bool) {
+	var (
+		employee  Employee
+		employees []*Employee
+		order     Order
+		dto       UserDTO
+	)
+
+	copier.Copy(&employee, &user)
+	copier.Copy(&employees, users)
+	copier.Copy(&employee, value)
+	copier.Copy(value, &user)
+
+	copier.Copy(employee, &user) // want `copier.Copy to non-pointer a.Employee: copy to value is unaddressable`
+	copier.Copy(employees, user) // want `copier.Copy to non-pointer \[\]\*a.Employee: copy to value is unaddressable`
+
+	copier.Copy(&order, &user) // want `copier.Copy from a.User to a.Order copies no fields`
+
+	// hooks may set the fields nothing is copied to
+	copier.Copy(&Greeting{}, &user)
+	copier.Copy(&[]Stamp{}, users)
+
+	copier.Copy(&order, &AuditedOrder{}) // want `copier.Copy from a.AuditedOrder to a.Order: ambiguous source fields ID`
+	copier.Copy(&AuditedOrder{}, &order) // want `copier.Copy from a.Order to a.AuditedOrder: ambiguous destination fields ID`
+	copier.Copy(&order, &DiamondOrder{}) // want `copier.Copy from a.DiamondOrder to a.Order: ambiguous source fields ID`
+	copier.Copy(&DiamondOrder{}, &order) // want `copier.Copy from a.Order to a.DiamondOrder: ambiguous destination fields ID`
+
+	copier.CopyWithOption(&dto, &Account{}, copier.Option{TagMatch: "json"})
+	copier.Copy(&dto, &Account{}) // want `copier.Copy from a.Account to a.UserDTO copies no fields`
+
+	copier.CopyWithOption(&employee, &user, copier.Option{DisallowUnknownSource: true}) // want `copier.CopyWithOption from a.User to a.Employee: unknown source fields Employe \(did you mean EmployeId\?\)`
+	copier.CopyWithOption(&employee, &user, copier.Option{RequireAllDest: true})        // want `copier.CopyWithOption from a.User to a.Employee: missing destination fields EmployeId \(did you mean Employe\?\), SuperRule`
+
+	copier.CopyWithOption(&ContactDTO{}, &Contact{}, copier.Option{GetterPrefix: "Get", RequireAllDest: true})
+	copier.CopyWithOption(&Contact{}, &ContactDTO{}, copier.Option{SetterPrefix: "Set", DisallowUnknownSource: true})
+	copier.CopyWithOption(&Contact{}, &Card{}, copier.Option{SetterPrefix: "Set"})
+	copier.CopyWithOption(&ContactDTO{}, &Contact{}, copier.Option{RequireAllDest: true}) // want `copier.CopyWithOption from a.Contact to a.ContactDTO copies no fields`
+
+	opt := copier.Option{RequireAllDest: true}
+	copier.CopyWithOption(&employee, &user, opt)
+
+	// options only known at runtime aren't checked
+	jsonOpt := copier.Option{TagMatch: "json"}
+	copier.CopyWithOption(&dto, &Account{}, jsonOpt)
+	copier.CopyWithOption(&dto, &Account{}, copier.Option{TagMatch: tag})
+	copier.CopyWithOption(&employee, &user, copier.Option{RequireAllDest: strict})
+	copier.CopyWithOption(employee, &user, jsonOpt) // want `copier.CopyWithOption to non-pointer a.Employee: copy to value is unaddressable`
+}
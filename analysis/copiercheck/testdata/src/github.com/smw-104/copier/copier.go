@@ -0,0 +1,26 @@
+package copier
+
+type Option struct {
+	TagMatch              string
+	DisallowUnknownSource bool
+	RequireAllDest        bool
+	Unflatten             bool
+	GetterPrefix          string
+	SetterPrefix          string
+}
+
+func Copy(toValue interface{}, fromValue interface{}) (err error) {
+	return nil
+}
+
+func CopyWithOption(toValue interface{}, fromValue interface{}, opt Option) (err error) {
+	return nil
+}
+
+type BeforeCopier interface {
+	BeforeCopy(from interface{}) error
+}
+
+type AfterCopier interface {
+	AfterCopy(from interface{}) error
+}
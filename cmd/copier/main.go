@@ -0,0 +1,176 @@
+// The copier command inspects how copier.Copy maps fields between types.
+//
+// Usage:
+//
+//	copier explain [-json | -markdown] [-tag key] [-unflatten] [-getter prefix] [-setter prefix] <from> <to>
+//
+// The types are given as package.Type, for example:
+//
+//	copier explain ./internal/model.User ./api.UserDTO
+//
+// prints how Copy copies a model.User to an api.UserDTO, with the same
+// resolution rules as copier.Explain.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/smw-104/copier/internal/mapping"
+	"github.com/smw-104/copier/internal/mapping/gotypes"
+)
+
+const usage = `usage: copier explain [-json | -markdown] [-tag key] [-unflatten] [-getter prefix] [-setter prefix] <from> <to>
+
+Explain prints how copier.Copy copies the type <from> to the type <to>, types
+are given as package.Type, for example ./internal/model.User.
+
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "explain" {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	var (
+		jsonOutput     = flags.Bool("json", false, "print the mapping as JSON")
+		markdownOutput = flags.Bool("markdown", false, "print the mapping as a Markdown table")
+		tagMatch       = flags.String("tag", "", "match fields by the names in this struct tag key, like copier.Option.TagMatch")
+		unflatten      = flags.Bool("unflatten", false, "copy fields to the nested fields their names concatenate, like copier.Option.Unflatten")
+		getterPrefix   = flags.String("getter", "", "copy fields from getters with this name prefix, like copier.Option.GetterPrefix")
+		setterPrefix   = flags.String("setter", "", "copy fields to setters with this name prefix, like copier.Option.SetterPrefix")
+	)
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 || (*jsonOutput && *markdownOutput) {
+		flags.Usage()
+		return 2
+	}
+
+	plan, err := explain(flags.Arg(1), flags.Arg(0), mapping.Options{
+		TagMatch:     *tagMatch,
+		Unflatten:    *unflatten,
+		GetterPrefix: *getterPrefix,
+		SetterPrefix: *setterPrefix,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "copier: %v\n", err)
+		return 1
+	}
+
+	switch {
+	case *jsonOutput:
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(plan)
+	case *markdownOutput:
+		_, err = io.WriteString(stdout, markdown(plan))
+	default:
+		_, err = io.WriteString(stdout, plan.String())
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "copier: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// explain describes how copier copies the type named from to the type named to
+func explain(to, from string, opts mapping.Options) (*mapping.Plan, error) {
+	toType, err := lookupType(to)
+	if err != nil {
+		return nil, err
+	}
+	fromType, err := lookupType(from)
+	if err != nil {
+		return nil, err
+	}
+
+	if toType.Kind() != mapping.Struct || fromType.Kind() != mapping.Struct {
+		return nil, fmt.Errorf("explain copy from %v to %v: both types must be structs", fromType, toType)
+	}
+	return mapping.Resolve(toType, fromType, opts).Plan(), nil
+}
+
+// lookupType loads the type named package.Type
+func lookupType(name string) (mapping.Type, error) {
+	idx := strings.LastIndex(name, ".")
+	if idx <= strings.LastIndex(name, "/") {
+		return nil, fmt.Errorf("type %q should be given as package.Type", name)
+	}
+	pattern, typeName := name[:idx], name[idx+1:]
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedTypes}, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%q matches %v packages", pattern, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, pkg.Errors[0]
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %v not found in %v", typeName, pkg.PkgPath)
+	}
+	return mapping.Indirect(gotypes.Of(obj.Type())), nil
+}
+
+// markdown formats plan as a Markdown table
+func markdown(plan *mapping.Plan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### `%v` → `%v`\n\n", plan.From, plan.To)
+	fmt.Fprintln(&b, "| Destination | Source | Conversion | Warnings |")
+	fmt.Fprintln(&b, "| --- | --- | --- | --- |")
+	for _, field := range plan.Fields {
+		fmt.Fprintf(&b, "| `%v` | %v | %v | %v |\n", field.DestName(), markdownCode(field.SourceName()), field.Conversion,
+			strings.ReplaceAll(strings.Join(field.Warnings, "; "), "|", "\\|"))
+	}
+
+	if len(plan.UnusedSource) > 0 {
+		fmt.Fprintf(&b, "\nUnused source fields: %v\n", markdownCodes(plan.UnusedSource))
+	}
+	if len(plan.UnusedSetters) > 0 {
+		fmt.Fprintf(&b, "\nUnused setters: %v\n", markdownCodes(plan.UnusedSetters))
+	}
+	return b.String()
+}
+
+func markdownCode(name string) string {
+	if name == "-" {
+		return name
+	}
+	return "`" + name + "`"
+}
+
+func markdownCodes(names []string) string {
+	codes := make([]string, len(names))
+	for i, name := range names {
+		codes[i] = markdownCode(name)
+	}
+	return strings.Join(codes, ", ")
+}
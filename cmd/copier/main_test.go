@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+func TestExplain(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"explain", "./testdata/model.User", "./testdata/api.UserDTO"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Should exit with 0, but got %v: %v", code, stderr.String())
+	}
+
+	expected := `model.User -> api.UserDTO
+DEST       SOURCE       CONVERSION  WARNINGS
+UserID     -            none
+Name       Name         assign
+Age        Age          convert
+DoubleAge  DoubleAge()  assign
+Email      Email        valuer
+EmployeId  -            none        did you mean Employe?
+SuperRule  -            none
+Role()     Role         assign
+unused source fields: ID, Employe
+`
+	if stdout.String() != expected {
+		t.Errorf("Unexpected output\n%v", stdout.String())
+	}
+}
+
+func TestExplainJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"explain", "-json", "-tag", "json", "./testdata/model.User", "./testdata/api.UserDTO"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Should exit with 0, but got %v: %v", code, stderr.String())
+	}
+
+	var plan mapping.Plan
+	if err := json.Unmarshal(stdout.Bytes(), &plan); err != nil {
+		t.Fatalf("Should print JSON, but got %v", err)
+	}
+
+	if field := plan.Fields[0]; field.Dest != "UserID" || field.Source != "ID" || field.Conversion != mapping.ConversionAssign {
+		t.Errorf("UserID should be copied from ID by json tag, but got %+v", field)
+	}
+	if len(plan.UnusedSource) != 1 || plan.UnusedSource[0] != "Employe" {
+		t.Errorf("Unexpected unused source fields %v", plan.UnusedSource)
+	}
+}
+
+func TestExplainMarkdown(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"explain", "-markdown", "./testdata/model.User", "./testdata/api.UserDTO"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Should exit with 0, but got %v: %v", code, stderr.String())
+	}
+
+	for _, line := range []string{
+		"### `model.User` → `api.UserDTO`",
+		"| `DoubleAge` | `DoubleAge()` | assign |  |",
+		"| `EmployeId` | - | none | did you mean Employe? |",
+		"Unused source fields: `ID`, `Employe`",
+	} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("Output should contain %q, but got\n%v", line, stdout.String())
+		}
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"explain", "./testdata/model.Nope", "./testdata/api.UserDTO"},
+		{"explain", "./testdata/model", "./testdata/api.UserDTO"},
+		{"explain", "./testdata/model.User"},
+		{"explain", "-json", "-markdown", "./testdata/model.User", "./testdata/api.UserDTO"},
+		{"inspect"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code == 0 || stderr.Len() == 0 {
+			t.Errorf("%v should fail with an error, but got %v", args, code)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package api
+
+type UserDTO struct {
+	UserID    int64 `json:"user_id"`
+	Name      string
+	Age       int64
+	DoubleAge int32
+	Email     *string
+	EmployeId int64
+	SuperRule string
+}
+
+func (dto *UserDTO) Role(role string) {
+	dto.SuperRule = "Super " + role
+}
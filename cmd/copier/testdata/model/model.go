@@ -0,0 +1,16 @@
+package model
+
+import "database/sql"
+
+type User struct {
+	ID      int64 `json:"user_id"`
+	Name    string
+	Role    string
+	Age     int32
+	Email   sql.NullString
+	Employe int64
+}
+
+func (user *User) DoubleAge() int32 {
+	return 2 * user.Age
+}
@@ -4,11 +4,66 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/smw-104/copier/internal/mapping"
 )
 
+// Option sets copy options
+type Option struct {
+	// TagMatch names a struct tag key (e.g. "json" or "db") whose name is used
+	// to match fields, falling back to the Go field name. Fields tagged "-"
+	// are excluded.
+	TagMatch string
+
+	// DisallowUnknownSource returns an error when an exported source field has
+	// no destination field or setter to copy to.
+	DisallowUnknownSource bool
+
+	// RequireAllDest returns an error when an exported destination field gets
+	// no value from a source field or getter.
+	RequireAllDest bool
+
+	// Unflatten copies source fields to the nested destination fields their
+	// names concatenate, e.g. CustomerName to Customer.Name. Flattening, e.g.
+	// Customer.Name to CustomerName, is always done for destination fields
+	// without a source field or getter of the same name.
+	Unflatten bool
+
+	// GetterPrefix and SetterPrefix name getters and setters besides methods
+	// named like the field, e.g. "Get" and "Set" copy Name from GetName() and
+	// to SetName(name), like protobuf or Java style types.
+	GetterPrefix string
+	SetterPrefix string
+
+	// OnField is called after Copy writes a destination field, with the path
+	// of the field from the copied value (e.g. "Address.City" or "Items[0].Name"),
+	// the source value, the destination field and its value before the write.
+	// Nested structs copied field by field report their fields instead, and
+	// the fields a setter changes are reported after calling it.
+	OnField func(path string, from, to reflect.Value, oldTo interface{})
+
+	// path is the path of the struct being copied, for OnField
+	path string
+
+	// mapper is the configuration of the Mapper that is copying
+	mapper *mapperConfig
+}
+
 // Copy copy things
 func Copy(toValue interface{}, fromValue interface{}) (err error) {
+	return copier(toValue, fromValue, Option{})
+}
+
+// CopyWithOption copy with option
+func CopyWithOption(toValue interface{}, fromValue interface{}, opt Option) (err error) {
+	return copier(toValue, fromValue, opt)
+}
+
+func copier(toValue interface{}, fromValue interface{}, opt Option) (err error) {
 	var (
 		isSlice bool
 		amount  = 1
@@ -39,6 +94,21 @@ func Copy(toValue interface{}, fromValue interface{}) (err error) {
 		return
 	}
 
+	var (
+		typeMapping = mapTypes(toType, fromType, opt)
+		funcs       map[string]reflect.Value
+	)
+	if config := opt.mapper; config != nil {
+		// the mapper only configures its own types, nested structs are copied as usual
+		opt.mapper = nil
+		if config.toType == toType && config.fromType == fromType {
+			typeMapping, funcs = config.mapping, config.funcs
+		}
+	}
+	if err := checkMapping(typeMapping, toType, fromType, opt); err != nil {
+		return err
+	}
+
 	if to.Kind() == reflect.Slice {
 		isSlice = true
 		if from.Kind() == reflect.Slice {
@@ -47,7 +117,10 @@ func Copy(toValue interface{}, fromValue interface{}) (err error) {
 	}
 
 	for i := 0; i < amount; i++ {
-		var dest, source reflect.Value
+		var (
+			dest, source reflect.Value
+			elemOpt      = opt
+		)
 
 		//srcFieldValue := srcValue.FieldByName(f)
 		//srcFieldType, srcFieldFound := srcValue.Type().FieldByName(f)
@@ -63,6 +136,7 @@ func Copy(toValue interface{}, fromValue interface{}) (err error) {
 			}
 			// dest
 			dest = indirect(reflect.New(toType).Elem())
+			elemOpt.path = fmt.Sprintf("%v[%d]", opt.path, to.Len())
 		} else {
 			source = indirect(from)
 			dest = indirect(to)
@@ -70,114 +144,28 @@ func Copy(toValue interface{}, fromValue interface{}) (err error) {
 
 		// Valuer -> ptr
 		if source.IsValid() {
-			fromTypeFields := deepFields(fromType)
-			//fmt.Printf("%#v", fromTypeFields)
-			// Copy from field to field or method
-			for _, field := range fromTypeFields {
-				name := field.Name
-
-				if fromField := source.FieldByName(name); fromField.IsValid() {
-					// has field
-					if toField := dest.FieldByName(name); toField.IsValid() {
-						if isNullableType(fromField.Type()) && toField.Kind() == reflect.Ptr {
-							// We have same nullable type on both sides
-							if fromField.Type().AssignableTo(toField.Type()) {
-								toField.Set(fromField)
-								continue
-							}
-
-							v, _ := fromField.Interface().(driver.Valuer).Value()
-							if v == nil {
-								if toField.Kind() == reflect.Ptr {
-									pf := reflect.New(toField.Type().Elem())
-									if pf.Elem().Kind() == reflect.Ptr {
-										toField.Set(pf)
-									}
-								}
-								continue
-							}
-
-							valueType := reflect.TypeOf(v)
-
-							ptr := reflect.New(valueType)
-							ptr.Elem().Set(reflect.ValueOf(v))
-
-							assignableToField := toField
-							assignableFieldType := assignableToField.Type()
-							previousAssignableToField := assignableToField
-							for assignableToField.Kind() == reflect.Ptr {
-								previousAssignableToField = assignableToField
-								assignableToField.Set(reflect.New(assignableToField.Type().Elem()))
-								assignableToField = reflect.Indirect(assignableToField)
-								assignableFieldType = assignableFieldType.Elem()
-							}
-
-							if valueType.AssignableTo(assignableFieldType) { //toField.Type().Elem()
-								previousAssignableToField.Set(ptr)
-							}
-
-							continue
-						} else if isNullableType(fromField.Type()) {
-							// We have same nullable type on both sides
-							if fromField.Type().AssignableTo(toField.Type()) {
-								toField.Set(fromField)
-								continue
-							}
-
-							v, _ := fromField.Interface().(driver.Valuer).Value()
-							if v == nil {
-								continue
-							}
-
-							rv := reflect.ValueOf(v)
-							if rv.Type().AssignableTo(toField.Type()) {
-								toField.Set(rv)
-							}
-
-							continue
-						}
-
-						if toField.CanSet() {
-							if !set(toField, fromField) {
-								if err := Copy(toField.Addr().Interface(), fromField.Interface()); err != nil {
-									return err
-								}
-							}
-						}
-					} else {
-						// try to set to method
-						var toMethod reflect.Value
-						if dest.CanAddr() {
-							toMethod = dest.Addr().MethodByName(name)
-						} else {
-							toMethod = dest.MethodByName(name)
-						}
-
-						if toMethod.IsValid() && toMethod.Type().NumIn() == 1 && fromField.Type().AssignableTo(toMethod.Type().In(0)) {
-							toMethod.Call([]reflect.Value{fromField})
-						}
-					}
-				}
+			if !source.CanAddr() {
+				// make source addressable so methods with pointer receivers can be called
+				addressable := reflect.New(source.Type()).Elem()
+				addressable.Set(source)
+				source = addressable
 			}
 
-			// Copy from method to field
-			for _, field := range deepFields(toType) {
-				name := field.Name
+			if beforeCopier, ok := dest.Addr().Interface().(BeforeCopier); ok {
+				if err := beforeCopier.BeforeCopy(source.Addr().Interface()); err != nil {
+					return err
+				}
+			}
 
-				var fromMethod reflect.Value
-				if source.CanAddr() {
-					fromMethod = source.Addr().MethodByName(name)
-				} else {
-					fromMethod = source.MethodByName(name)
+			for _, field := range typeMapping.Fields {
+				if err := copyMapped(dest, source, field, funcs, elemOpt); err != nil {
+					return err
 				}
+			}
 
-				if fromMethod.IsValid() && fromMethod.Type().NumIn() == 0 && fromMethod.Type().NumOut() == 1 {
-					if toField := dest.FieldByName(name); toField.IsValid() && toField.CanSet() {
-						values := fromMethod.Call([]reflect.Value{})
-						if len(values) >= 1 {
-							set(toField, values[0])
-						}
-					}
+			if afterCopier, ok := dest.Addr().Interface().(AfterCopier); ok {
+				if err := afterCopier.AfterCopy(source.Addr().Interface()); err != nil {
+					return err
 				}
 			}
 		}
@@ -192,25 +180,426 @@ func Copy(toValue interface{}, fromValue interface{}) (err error) {
 	return
 }
 
-func isNullableType(t reflect.Type) bool {
-	return t.ConvertibleTo(reflect.TypeOf((*driver.Valuer)(nil)).Elem())
+// copyMapped copies the struct source to the struct dest as field describes,
+// source must be addressable
+func copyMapped(dest, source reflect.Value, field mapping.FieldMapping, funcs map[string]reflect.Value, opt Option) error {
+	fieldOpt := opt.field(field.ToField)
+	switch {
+	case field.Conversion == mapping.ConversionFunc:
+		// Copy from func to field
+		arg := source
+		if field.FromField != "" {
+			if arg = fieldByPath(source, field.FromField); !arg.IsValid() {
+				return nil
+			}
+		}
+		return setFromFunc(allocFieldByPath(dest, field.ToField), arg, funcs[field.ToField], fieldOpt)
+	case field.FromMethod != "" && field.ToMethod != "":
+		// Copy from method to method
+		if nilReceiver(source, field.FromMethod) {
+			return nil
+		}
+		name := opt.accessorField(field.FromMethod, field.ToMethod)
+		value, err := callGetter(source.Addr().MethodByName(field.FromMethod), opt.field(name))
+		if err != nil {
+			return err
+		}
+		return callSetter(dest, field.ToMethod, value, opt, name)
+	case field.FromMethod != "":
+		// Copy from method to field
+		if nilReceiver(source, field.FromMethod) {
+			return nil
+		}
+		if toField := allocFieldByPath(dest, field.ToField); toField.IsValid() && toField.CanSet() {
+			value, err := callGetter(source.Addr().MethodByName(field.FromMethod), fieldOpt)
+			if err != nil {
+				return err
+			}
+			oldTo := fieldOpt.snapshot(toField)
+			if set(toField, value) {
+				fieldOpt.written(value, toField, oldTo)
+			}
+		}
+	case field.ToMethod != "":
+		// Copy from field to method
+		if fromField := fieldByPath(source, field.FromField); fromField.IsValid() {
+			return callSetter(dest, field.ToMethod, fromField, opt, field.FromField)
+		}
+	default:
+		// Copy from field to field
+		if fromField := fieldByPath(source, field.FromField); fromField.IsValid() {
+			if toField := allocFieldByPath(dest, field.ToField); toField.IsValid() {
+				return copyField(toField, fromField, fieldOpt)
+			}
+		}
+	}
+	return nil
 }
 
-func deepFields(reflectType reflect.Type) []reflect.StructField {
-	var fields []reflect.StructField
+// fieldByPath returns the field of the struct v at the dotted path, or an
+// invalid value when a pointer on the way is nil
+func fieldByPath(v reflect.Value, path string) reflect.Value {
+	for i := 0; ; i++ {
+		// cut instead of split, paths are looked up for every copied field
+		name, rest, nested := strings.Cut(path, ".")
+		if i > 0 {
+			if v = indirect(v); !v.IsValid() {
+				return v
+			}
+		}
+		if v = fieldByName(v, name); !nested {
+			return v
+		}
+		path = rest
+	}
+}
 
-	if reflectType = indirectType(reflectType); reflectType.Kind() == reflect.Struct {
-		for i := 0; i < reflectType.NumField(); i++ {
-			v := reflectType.Field(i)
-			if v.Anonymous {
-				fields = append(fields, deepFields(v.Type)...)
-			} else {
-				fields = append(fields, v)
+// allocFieldByPath returns the field of the struct v at the dotted path,
+// allocating nil pointers on the way
+func allocFieldByPath(v reflect.Value, path string) reflect.Value {
+	for i := 0; ; i++ {
+		name, rest, nested := strings.Cut(path, ".")
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return v
+		}
+		if v = allocFieldByName(v, name); !nested {
+			return v
+		}
+		path = rest
+	}
+}
+
+// fieldByName returns the field of the struct v by name, or an invalid value
+// when it's promoted through a nil embedded pointer
+func fieldByName(v reflect.Value, name string) reflect.Value {
+	field, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}
+	}
+	value, err := v.FieldByIndexErr(field.Index)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return value
+}
+
+// allocFieldByName returns the field of the struct v by name, allocating nil
+// embedded pointers it's promoted through
+func allocFieldByName(v reflect.Value, name string) reflect.Value {
+	field, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}
+	}
+	return allocFieldByIndex(v, field.Index)
+}
+
+// callGetter returns the value of the getter fn, or the error it returns for
+// the field at the path of opt
+func callGetter(fn reflect.Value, opt Option) (reflect.Value, error) {
+	values := fn.Call(nil)
+	if len(values) == 2 && !values[1].IsNil() {
+		return reflect.Value{}, fmt.Errorf("copy %v: %w", opt.path, values[1].Interface().(error))
+	}
+	return values[0], nil
+}
+
+// callSetter calls the setter name of the struct dest with value, returning
+// its error for the field path of opt. The fields of dest the setter changes
+// are reported to OnField.
+func callSetter(dest reflect.Value, name string, value reflect.Value, opt Option, path string) error {
+	var before reflect.Value
+	if opt.OnField != nil {
+		before = deepCopy(dest)
+	}
+
+	results := dest.Addr().MethodByName(name).Call([]reflect.Value{value})
+	if len(results) > 0 {
+		if last := results[len(results)-1]; last.Type() == errorType && !last.IsNil() {
+			return fmt.Errorf("copy %v: %w", opt.field(path).path, last.Interface().(error))
+		}
+	}
+
+	if opt.OnField != nil {
+		for _, field := range reflect.VisibleFields(dest.Type()) {
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			toField, err := dest.FieldByIndexErr(field.Index)
+			if err != nil {
+				continue
+			}
+			if oldTo := fieldByIndex(before, field.Index).Interface(); !reflect.DeepEqual(oldTo, toField.Interface()) {
+				opt.field(field.Name).written(value, toField, oldTo)
+			}
+		}
+	}
+	return nil
+}
+
+// accessorField returns the name of the field the getter and setter of a
+// method to method copy are for, e.g. Age for GetAge and SetAge
+func (opt Option) accessorField(getter, setter string) string {
+	for _, name := range []string{getter, strings.TrimPrefix(getter, opt.GetterPrefix)} {
+		if setter == name || setter == opt.SetterPrefix+name {
+			return name
+		}
+	}
+	return setter
+}
+
+// nilReceiver reports whether the method name of the struct v is promoted
+// through a nil embedded pointer or interface that calling it dereferences,
+// so calling it panics. Methods on pointer receivers get the nil pointer,
+// like protobuf's nil safe getters. Like field selectors, the method comes
+// from the shallowest embedded type declaring it, which shadows deeper ones.
+func nilReceiver(v reflect.Value, name string) bool {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []embedded{{typ: v.Type()}}
+	visited := map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var next, declaring []embedded
+		for _, parent := range current {
+			if visited[parent.typ] {
+				continue
+			}
+			visited[parent.typ] = true
+			for i := 0; i < parent.typ.NumField(); i++ {
+				field := parent.typ.Field(i)
+				if !field.Anonymous {
+					continue
+				}
+				child := embedded{typ: field.Type, index: append(append([]int(nil), parent.index...), i)}
+				if child.typ.Kind() == reflect.Ptr {
+					child.typ = child.typ.Elem()
+				}
+				if declaresMethod(child.typ, name) {
+					declaring = append(declaring, child)
+				} else if child.typ.Kind() == reflect.Struct {
+					next = append(next, child)
+				}
+			}
+		}
+		if len(declaring) > 0 {
+			// several declarations at the same depth are ambiguous, so the
+			// struct has no such method to call unless it declares one
+			return len(declaring) == 1 && nilEmbedded(v, declaring[0].index, name) && !declaresMethod(v.Type(), name)
+		}
+		current = next
+	}
+	return false
+}
+
+// nilEmbedded reports whether calling the method name of the embedded field
+// at index of the struct v dereferences a nil pointer or interface
+func nilEmbedded(v reflect.Value, index []int, name string) bool {
+	for i, x := range index {
+		field := v.Field(x)
+		if i == len(index)-1 {
+			if field.Kind() == reflect.Interface {
+				return field.IsNil()
+			}
+			if field.Kind() == reflect.Ptr && field.IsNil() {
+				_, valueReceiver := field.Type().Elem().MethodByName(name)
+				return valueReceiver
+			}
+			return false
+		}
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				return true
+			}
+			field = field.Elem()
+		}
+		v = field
+	}
+	return false
+}
+
+// declaresMethod reports whether the type t declares the method name itself
+// on its value or pointer receiver, rather than promoting it from an embedded
+// field. Promoted methods are compiled to autogenerated wrappers.
+func declaresMethod(t reflect.Type, name string) bool {
+	if t.Kind() == reflect.Interface {
+		_, ok := t.MethodByName(name)
+		return ok
+	}
+	for _, typ := range []reflect.Type{t, reflect.PtrTo(t)} {
+		if method, ok := typ.MethodByName(name); ok {
+			pc := method.Func.Pointer()
+			if file, _ := runtime.FuncForPC(pc).FileLine(pc); file != "<autogenerated>" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allocFieldByIndex returns the promoted field of the struct v, allocating
+// nil embedded pointers, or an invalid value when an unexported one is nil
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
 			}
+			v = v.Elem()
 		}
+		v = v.Field(x)
 	}
+	return v
+}
 
-	return fields
+// copyField copies fromField to toField, reporting the write to OnField
+func copyField(toField, fromField reflect.Value, opt Option) error {
+	oldTo := opt.snapshot(toField)
+	written, err := copyValue(toField, fromField, opt)
+	if written {
+		opt.written(fromField, toField, oldTo)
+	}
+	return err
+}
+
+// field returns opt for copying the destination field name
+func (opt Option) field(name string) Option {
+	if opt.path != "" {
+		name = opt.path + "." + name
+	}
+	opt.path = name
+	return opt
+}
+
+// snapshot returns the value of toField before it's written, for OnField.
+// Pointers are copied as set writes through them.
+func (opt Option) snapshot(toField reflect.Value) interface{} {
+	if opt.OnField == nil {
+		return nil
+	}
+	return snapshot(toField)
+}
+
+// snapshot returns the value of v, copying what pointers point to
+func snapshot(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		value := reflect.New(v.Type().Elem())
+		value.Elem().Set(v.Elem())
+		return value.Interface()
+	}
+	return v.Interface()
+}
+
+// written calls OnField after toField was set from from
+func (opt Option) written(from, toField reflect.Value, oldTo interface{}) {
+	if opt.OnField != nil {
+		opt.OnField(opt.path, from, toField, oldTo)
+	}
+}
+
+// copyValue copies fromField to toField, converting between nullable types and
+// copying structs recursively. It reports whether toField was set directly.
+func copyValue(toField, fromField reflect.Value, opt Option) (bool, error) {
+	if isNullableType(fromField.Type()) && toField.Kind() == reflect.Ptr {
+		// We have same nullable type on both sides
+		if fromField.Type().AssignableTo(toField.Type()) {
+			toField.Set(fromField)
+			return true, nil
+		}
+
+		v, _ := fromField.Interface().(driver.Valuer).Value()
+		if v == nil {
+			if toField.Kind() == reflect.Ptr {
+				pf := reflect.New(toField.Type().Elem())
+				if pf.Elem().Kind() == reflect.Ptr {
+					toField.Set(pf)
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+
+		valueType := reflect.TypeOf(v)
+
+		ptr := reflect.New(valueType)
+		ptr.Elem().Set(reflect.ValueOf(v))
+
+		assignableToField := toField
+		assignableFieldType := assignableToField.Type()
+		previousAssignableToField := assignableToField
+		for assignableToField.Kind() == reflect.Ptr {
+			previousAssignableToField = assignableToField
+			assignableToField.Set(reflect.New(assignableToField.Type().Elem()))
+			assignableToField = reflect.Indirect(assignableToField)
+			assignableFieldType = assignableFieldType.Elem()
+		}
+
+		if valueType.AssignableTo(assignableFieldType) { //toField.Type().Elem()
+			previousAssignableToField.Set(ptr)
+		}
+
+		return true, nil
+	} else if isNullableType(fromField.Type()) {
+		// We have same nullable type on both sides
+		if fromField.Type().AssignableTo(toField.Type()) {
+			toField.Set(fromField)
+			return true, nil
+		}
+
+		v, _ := fromField.Interface().(driver.Valuer).Value()
+		if v == nil {
+			return false, nil
+		}
+
+		rv := reflect.ValueOf(v)
+		if rv.Type().AssignableTo(toField.Type()) {
+			toField.Set(rv)
+			return true, nil
+		}
+
+		return false, nil
+	}
+
+	if toField.CanSet() {
+		if set(toField, fromField) {
+			return true, nil
+		}
+		// copy field by field, the fields are reported instead
+		return false, copier(toField.Addr().Interface(), fromField.Interface(), opt)
+	}
+	return false, nil
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+func isNullableType(t reflect.Type) bool {
+	return t.ConvertibleTo(valuerType)
+}
+
+func isNilable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return true
+	}
+	return false
 }
 
 func indirect(reflectValue reflect.Value) reflect.Value {
@@ -238,7 +627,7 @@ func set(to, from reflect.Value) bool {
 			if from.Kind() == reflect.Ptr && from.IsNil() {
 				to.Set(reflect.Zero(to.Type()))
 				return true
-			} else if from.Kind() != reflect.String && from.IsNil() && to.IsNil() && to.Kind() == reflect.Ptr {
+			} else if isNilable(from) && from.IsNil() && to.IsNil() && to.Kind() == reflect.Ptr {
 				pf := reflect.New(to.Type().Elem())
 				if pf.Elem().Kind() == reflect.Ptr {
 					to.Set(pf)
@@ -3,7 +3,9 @@ package copier
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -323,6 +325,35 @@ func TestCopySameStructWithPointerField(t *testing.T) {
 	}
 }
 
+func TestCopyValuesToNilPointerFields(t *testing.T) {
+	type Title struct {
+		Text string
+	}
+
+	type From struct {
+		Age    int
+		Title  Title
+		Scores []int
+	}
+
+	type To struct {
+		Age    *int64
+		Title  *Title
+		Scores *[]int
+	}
+
+	var to To
+	if err := Copy(&to, &From{Age: 18, Title: Title{"jinzhu"}}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if to.Age == nil || *to.Age != 18 || to.Title == nil || to.Title.Text != "jinzhu" {
+		t.Errorf("Should allocate pointer fields for values, but got %+v", to)
+	}
+	if to.Scores != nil {
+		t.Errorf("Should leave pointers to nil slices nil, but got %v", *to.Scores)
+	}
+}
+
 func checkEmployee2(employee Employee, user *User, t *testing.T, testCase string) {
 	if user == nil {
 		if employee.Name != "" || employee.Nickname != nil || employee.Birthday != nil || employee.Age != 0 ||
@@ -573,3 +604,765 @@ func TestScanner(t *testing.T) {
 		t.Errorf("Field V should be copied")
 	}
 }
+
+type tagUser struct {
+	ID       int64  `json:"user_id,omitempty" db:"id"`
+	FullName string `json:"name"`
+	Password string `json:"-"`
+	Email    string
+}
+
+type tagEmployee struct {
+	UserID   int64  `json:"user_id" db:"user_id"`
+	Name     string `json:"name,omitempty"`
+	Password string
+	Email    string `json:"email"`
+}
+
+func TestCopyWithTagMatch(t *testing.T) {
+	user := tagUser{ID: 7, FullName: "Jinzhu", Password: "secret", Email: "jinzhu@example.com"}
+
+	employee := tagEmployee{}
+	if err := CopyWithOption(&employee, &user, Option{TagMatch: "json"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	if employee.UserID != user.ID {
+		t.Errorf("UserID should be copied by json tag name")
+	}
+	if employee.Name != user.FullName {
+		t.Errorf("Name should be copied by json tag name")
+	}
+	if employee.Password != "" {
+		t.Errorf("Password should be excluded by json tag")
+	}
+	if employee.Email != user.Email {
+		t.Errorf("Email should be copied by Go name")
+	}
+
+	employee2 := tagEmployee{}
+	if err := CopyWithOption(&employee2, &user, Option{TagMatch: "db"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	if employee2.UserID != 0 || employee2.Name != "" {
+		t.Errorf("Fields with different db tag names should not be copied")
+	}
+	if employee2.Password != user.Password || employee2.Email != user.Email {
+		t.Errorf("Fields without db tags should be copied by Go name")
+	}
+}
+
+func TestCopyStrict(t *testing.T) {
+	type Model struct {
+		Name  string
+		Email string
+		Phone string
+		Role  string
+		age   int
+	}
+
+	type DTO struct {
+		Name     string
+		Nickname string
+		Age      int
+		Email    string `json:"-"`
+	}
+
+	model := Model{Name: "Jinzhu", Email: "jinzhu@example.com", Phone: "123", Role: "Admin"}
+
+	if err := Copy(&DTO{}, &model); err != nil {
+		t.Errorf("Should not raise error without strict options, but got %v", err)
+	}
+
+	err := CopyWithOption(&DTO{}, &model, Option{DisallowUnknownSource: true})
+	if e, ok := err.(*UnmappedFieldsError); !ok {
+		t.Errorf("Should raise UnmappedFieldsError, but got %v", err)
+	} else if !reflect.DeepEqual(e.UnknownSource, []string{"Phone", "Role"}) || e.MissingDest != nil {
+		t.Errorf("Should report unknown source fields, but got %v", e)
+	}
+
+	// errors must not share the cached mapping
+	err.(*UnmappedFieldsError).UnknownSource[0] = "Changed"
+	err = CopyWithOption(&DTO{}, &model, Option{DisallowUnknownSource: true})
+	if e, ok := err.(*UnmappedFieldsError); !ok || !reflect.DeepEqual(e.UnknownSource, []string{"Phone", "Role"}) {
+		t.Errorf("Should not be changed by changing a previous error, but got %v", err)
+	}
+
+	err = CopyWithOption(&DTO{}, &model, Option{RequireAllDest: true, TagMatch: "json"})
+	if e, ok := err.(*UnmappedFieldsError); !ok {
+		t.Errorf("Should raise UnmappedFieldsError, but got %v", err)
+	} else if !reflect.DeepEqual(e.MissingDest, []string{"Nickname", "Age"}) || e.UnknownSource != nil {
+		t.Errorf("Should report missing destination fields, but got %v", e)
+	} else if e.Error() != "copy from copier.Model to copier.DTO: missing destination fields Nickname, Age" {
+		t.Errorf("Unexpected error message %q", e.Error())
+	}
+
+	var employee Employee
+	user := User{Name: "Jinzhu", Role: "Admin"}
+	err = CopyWithOption(&employee, &user, Option{DisallowUnknownSource: true})
+	if err != nil {
+		t.Errorf("Fields copied to setters should not be reported, but got %v", err)
+	}
+	err = CopyWithOption(&employee, &user, Option{RequireAllDest: true})
+	if e, ok := err.(*UnmappedFieldsError); !ok || !reflect.DeepEqual(e.MissingDest, []string{"EmployeID", "SuperRule"}) {
+		t.Errorf("Fields copied from getters should not be reported, but got %v", err)
+	}
+}
+
+func TestCopyStrictSuggestions(t *testing.T) {
+	type Model struct {
+		Name    string
+		Employe int64
+		Emial   string
+	}
+
+	type DTO struct {
+		NAME      string
+		EmployeId int64
+		Email     string
+	}
+
+	err := CopyWithOption(&DTO{}, &Model{}, Option{DisallowUnknownSource: true, RequireAllDest: true})
+	e, ok := err.(*UnmappedFieldsError)
+	if !ok {
+		t.Fatalf("Should raise UnmappedFieldsError, but got %v", err)
+	}
+
+	expected := map[string][]string{
+		"Name":      {"NAME"},
+		"Employe":   {"EmployeId"},
+		"Emial":     {"Email"},
+		"NAME":      {"Name"},
+		"EmployeId": {"Employe"},
+		"Email":     {"Emial"},
+	}
+	if !reflect.DeepEqual(e.Suggestions, expected) {
+		t.Errorf("Unexpected suggestions %v", e.Suggestions)
+	}
+
+	if msg := "copy from copier.Model to copier.DTO: unknown source fields Name (did you mean NAME?), Employe (did you mean EmployeId?), Emial (did you mean Email?); " +
+		"missing destination fields NAME (did you mean Name?), EmployeId (did you mean Employe?), Email (did you mean Emial?)"; e.Error() != msg {
+		t.Errorf("Unexpected error message %q", e.Error())
+	}
+}
+
+func TestCopyOnField(t *testing.T) {
+	type Address struct {
+		City string
+	}
+
+	type Item struct {
+		Name string
+	}
+
+	type Order struct {
+		Status  string
+		Count   *int
+		Address Address
+		Items   []Item
+	}
+
+	type OrderDTO struct {
+		Status  string
+		Count   int
+		Address struct{ City, Zip string }
+		Items   []struct{ Name string }
+	}
+
+	count := 1
+	order := Order{Status: "pending", Count: &count, Address: Address{City: "Shanghai"}}
+
+	var dto OrderDTO
+	dto.Status = "shipped"
+	dto.Count = 2
+	dto.Address.City = "Beijing"
+	dto.Items = append(dto.Items, struct{ Name string }{"book"})
+
+	var changes []string
+	err := CopyWithOption(&order, &dto, Option{OnField: func(path string, from, to reflect.Value, oldTo interface{}) {
+		switch old := oldTo.(type) {
+		case *int:
+			changes = append(changes, fmt.Sprintf("%v: %v -> %v", path, *old, to.Elem().Interface()))
+		default:
+			changes = append(changes, fmt.Sprintf("%v: %v -> %v", path, old, to.Interface()))
+		}
+	}})
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	expected := []string{"Status: pending -> shipped", "Count: 1 -> 2", "Address.City: Shanghai -> Beijing", "Items[0].Name:  -> book"}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Should report the written fields %v, but got %v", expected, changes)
+	}
+}
+
+func TestCopyTagPaths(t *testing.T) {
+	type Customer struct {
+		Name string
+	}
+
+	type Order struct {
+		ID       int64
+		Customer *Customer
+	}
+
+	type OrderDTO struct {
+		ID           int64
+		CustomerName string `copier:"Customer.Name"`
+	}
+
+	var dto OrderDTO
+	if err := Copy(&dto, &Order{ID: 1, Customer: &Customer{Name: "Jinzhu"}}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if dto.ID != 1 || dto.CustomerName != "Jinzhu" {
+		t.Errorf("Should flatten Customer.Name, but got %+v", dto)
+	}
+
+	dto = OrderDTO{CustomerName: "unchanged"}
+	if err := Copy(&dto, &Order{}); err != nil || dto.CustomerName != "unchanged" {
+		t.Errorf("Should skip paths through nil pointers, but got %+v, %v", dto, err)
+	}
+
+	var order Order
+	if err := CopyWithOption(&order, &OrderDTO{ID: 2, CustomerName: "Jinzhu"}, Option{DisallowUnknownSource: true, RequireAllDest: true}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if order.ID != 2 || order.Customer == nil || order.Customer.Name != "Jinzhu" {
+		t.Errorf("Should unflatten to Customer.Name allocating Customer, but got %+v", order)
+	}
+
+	var copied OrderDTO
+	if err := Copy(&copied, &OrderDTO{CustomerName: "Jinzhu"}); err != nil || copied.CustomerName != "Jinzhu" {
+		t.Errorf("Should copy tagged fields by name between the same types, but got %+v, %v", copied, err)
+	}
+}
+
+func TestCopyFlatten(t *testing.T) {
+	type Country struct {
+		Code string
+	}
+
+	type Customer struct {
+		Name    string
+		Country *Country
+	}
+
+	type Order struct {
+		ID       int64
+		Customer Customer
+	}
+
+	type OrderDTO struct {
+		ID                  int64
+		CustomerName        string
+		CustomerCountryCode string
+		CustomerAge         int
+	}
+
+	var dto OrderDTO
+	order := Order{ID: 1, Customer: Customer{Name: "Jinzhu", Country: &Country{Code: "CN"}}}
+	if err := Copy(&dto, &order); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if dto.CustomerName != "Jinzhu" || dto.CustomerCountryCode != "CN" {
+		t.Errorf("Should flatten nested fields, but got %+v", dto)
+	}
+
+	var copied Order
+	if err := Copy(&copied, &dto); err != nil || copied.Customer.Name != "" {
+		t.Errorf("Should not unflatten by default, but got %+v, %v", copied, err)
+	}
+
+	if err := CopyWithOption(&copied, &dto, Option{Unflatten: true}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if copied.ID != 1 || copied.Customer.Name != "Jinzhu" || copied.Customer.Country == nil || copied.Customer.Country.Code != "CN" {
+		t.Errorf("Should unflatten fields with Unflatten, but got %+v", copied)
+	}
+
+	type DirectDTO struct {
+		CustomerName string
+	}
+
+	type Direct struct {
+		Customer     Customer
+		CustomerName string
+	}
+
+	var direct DirectDTO
+	if err := Copy(&direct, &Direct{Customer: Customer{Name: "nested"}, CustomerName: "direct"}); err != nil || direct.CustomerName != "direct" {
+		t.Errorf("Should prefer the direct field, but got %+v, %v", direct, err)
+	}
+}
+
+func TestCopyEmbeddedNameCollisions(t *testing.T) {
+	type Audit struct {
+		ID      int64
+		Comment string
+	}
+
+	type Owner struct {
+		ID      int64
+		Comment string
+	}
+
+	type Shadowed struct {
+		Audit
+		Owner
+		ID int64
+	}
+
+	type Ambiguous struct {
+		Audit
+		Owner
+		Status string
+	}
+
+	type Order struct {
+		ID     int64
+		Status string
+	}
+
+	var order Order
+	if err := Copy(&order, &Shadowed{Audit: Audit{ID: 1}, Owner: Owner{ID: 2}, ID: 3}); err != nil || order.ID != 3 {
+		t.Errorf("Should copy the outer field shadowing the embedded ones, but got %+v, %v", order, err)
+	}
+
+	err := Copy(&order, &Ambiguous{Status: "paid"})
+	if e, ok := err.(*AmbiguousFieldsError); !ok || !reflect.DeepEqual(e.Source, []string{"ID"}) {
+		t.Fatalf("Should return the ambiguous source field, but got %v", err)
+	}
+	if msg := "copy from copier.Ambiguous to copier.Order: ambiguous source fields ID"; err.Error() != msg {
+		t.Errorf("Unexpected error message %q", err.Error())
+	}
+
+	err = Copy(&Ambiguous{}, &order)
+	if e, ok := err.(*AmbiguousFieldsError); !ok || !reflect.DeepEqual(e.Dest, []string{"ID"}) {
+		t.Errorf("Should return the ambiguous destination field, but got %v", err)
+	}
+
+	type Note struct {
+		Status string
+	}
+	if err := Copy(&Note{}, &Ambiguous{Status: "paid"}); err != nil {
+		t.Errorf("Should ignore ambiguous fields that aren't copied, but got %v", err)
+	}
+
+	type Common struct {
+		ID int64
+	}
+
+	type Left struct {
+		Common
+	}
+
+	type Right struct {
+		Common
+	}
+
+	type Diamond struct {
+		Left
+		Right
+		Status string
+	}
+
+	err = Copy(&order, &Diamond{Status: "paid"})
+	if e, ok := err.(*AmbiguousFieldsError); !ok || !reflect.DeepEqual(e.Source, []string{"ID"}) {
+		t.Errorf("Should return the field embedded twice through the same type as ambiguous, but got %v", err)
+	}
+
+	err = CopyWithOption(&Diamond{}, &order, Option{RequireAllDest: true})
+	if e, ok := err.(*AmbiguousFieldsError); !ok || !reflect.DeepEqual(e.Dest, []string{"ID"}) {
+		t.Errorf("Should return the destination field embedded twice as ambiguous, but got %v", err)
+	}
+}
+
+type embeddedName string
+
+type recursiveNode struct {
+	*recursiveNode
+	Name embeddedName
+}
+
+func TestCopyEmbeddedNonStruct(t *testing.T) {
+	type Tagged struct {
+		embeddedName
+		Title string
+	}
+
+	type Source struct {
+		Name  embeddedName
+		Title string
+	}
+
+	var node recursiveNode
+	if err := Copy(&node, &Source{Name: "root"}); err != nil || node.Name != "root" {
+		t.Errorf("Should copy types embedding themselves, but got %+v, %v", node, err)
+	}
+
+	var tagged Tagged
+	if err := Copy(&tagged, &Source{Title: "title"}); err != nil || tagged.Title != "title" {
+		t.Errorf("Should copy types embedding non structs, but got %+v, %v", tagged, err)
+	}
+}
+
+type embeddedAudit struct {
+	CreatedBy string
+}
+
+func TestCopyNilEmbeddedPointers(t *testing.T) {
+	type Audit struct {
+		CreatedBy string
+		UpdatedBy string
+	}
+
+	type Record struct {
+		*Audit
+		Name string
+	}
+
+	type Hidden struct {
+		*embeddedAudit
+		Name string
+	}
+
+	type Flat struct {
+		CreatedBy string
+		UpdatedBy string
+		Name      string
+	}
+
+	var record Record
+	if err := Copy(&record, &Flat{CreatedBy: "jinzhu", Name: "record"}); err != nil {
+		t.Fatalf("Should copy to nil embedded pointers, but got %v", err)
+	}
+	if record.Audit == nil || record.CreatedBy != "jinzhu" || record.Name != "record" {
+		t.Errorf("Should allocate nil embedded pointers, but got %+v", record)
+	}
+
+	var flat Flat
+	if err := Copy(&flat, &Record{Name: "record"}); err != nil {
+		t.Fatalf("Should copy from nil embedded pointers, but got %v", err)
+	}
+	if flat.CreatedBy != "" || flat.Name != "record" {
+		t.Errorf("Should treat nil embedded pointers as no values, but got %+v", flat)
+	}
+
+	var hidden Hidden
+	if err := Copy(&hidden, &Flat{CreatedBy: "jinzhu", Name: "hidden"}); err != nil {
+		t.Fatalf("Should skip fields of unexported nil embedded pointers, but got %v", err)
+	}
+	if hidden.embeddedAudit != nil || hidden.Name != "hidden" {
+		t.Errorf("Should leave unexported embedded pointers nil, but got %+v", hidden)
+	}
+
+	var records []Record
+	if err := Copy(&records, []Flat{{UpdatedBy: "a"}, {}}); err != nil {
+		t.Fatalf("Should copy slices to nil embedded pointers, but got %v", err)
+	}
+	if len(records) != 2 || records[0].Audit == nil || records[0].UpdatedBy != "a" {
+		t.Errorf("Should allocate nil embedded pointers of slice elements, but got %+v", records)
+	}
+}
+
+func TestCopyEmbeddedToNamedField(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int32
+	}
+
+	type PersonDTO struct {
+		Name string
+		Age  int64
+	}
+
+	type Employee struct {
+		*Person
+		Title string
+	}
+
+	type EmployeeDTO struct {
+		Person PersonDTO
+		Title  string
+	}
+
+	strict := Option{DisallowUnknownSource: true, RequireAllDest: true}
+
+	var dto EmployeeDTO
+	if err := CopyWithOption(&dto, &Employee{Person: &Person{Name: "jinzhu", Age: 18}, Title: "Dev"}, strict); err != nil {
+		t.Fatalf("Should copy embedded structs to named fields, but got %v", err)
+	}
+	if dto.Person.Name != "jinzhu" || dto.Person.Age != 18 || dto.Title != "Dev" {
+		t.Errorf("Should copy the embedded struct recursively, but got %+v", dto)
+	}
+
+	var employee Employee
+	if err := CopyWithOption(&employee, &dto, strict); err != nil {
+		t.Fatalf("Should copy named fields to embedded structs, but got %v", err)
+	}
+	if employee.Person == nil || employee.Name != "jinzhu" || employee.Age != 18 || employee.Title != "Dev" {
+		t.Errorf("Should copy to the embedded struct recursively, but got %+v", employee)
+	}
+
+	dto = EmployeeDTO{}
+	if err := CopyWithOption(&dto, &Employee{Title: "Dev"}, strict); err != nil || dto.Person != (PersonDTO{}) {
+		t.Errorf("Should copy nil embedded structs as zero values, but got %+v, %v", dto, err)
+	}
+}
+
+type accessorUser struct {
+	name string
+	age  int32
+}
+
+func (user *accessorUser) GetName() string {
+	if user == nil {
+		return ""
+	}
+	return user.name
+}
+
+func (user *accessorUser) SetName(name string) {
+	user.name = name
+}
+
+func (user *accessorUser) GetAge() int32 {
+	if user == nil {
+		return 0
+	}
+	return user.age
+}
+
+type accessorAudit struct {
+	by string
+}
+
+func (audit accessorAudit) GetUpdatedBy() string {
+	return audit.by
+}
+
+type titleBase struct{}
+
+func (titleBase) Title() string {
+	return "base"
+}
+
+type titleOuter struct {
+	*titleBase
+}
+
+func (*titleOuter) Title() string {
+	return "outer"
+}
+
+type titleMiddle struct {
+	*titleBase
+}
+
+func (*titleMiddle) Title() string {
+	return "middle"
+}
+
+type titleShadowed struct {
+	*titleMiddle
+}
+
+func TestCopyAccessorPrefixes(t *testing.T) {
+	type UserDTO struct {
+		Name string
+		Age  int64
+	}
+
+	accessors := Option{GetterPrefix: "Get", SetterPrefix: "Set"}
+
+	var dto UserDTO
+	if err := CopyWithOption(&dto, &accessorUser{name: "jinzhu", age: 18}, accessors); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if dto.Name != "jinzhu" || dto.Age != 18 {
+		t.Errorf("Should copy from prefixed getters, but got %+v", dto)
+	}
+
+	var user accessorUser
+	if err := CopyWithOption(&user, &UserDTO{Name: "copier"}, accessors); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if user.name != "copier" {
+		t.Errorf("Should copy to prefixed setters, but got %+v", user)
+	}
+
+	dto = UserDTO{}
+	if err := Copy(&dto, &accessorUser{name: "jinzhu"}); err != nil || dto.Name != "" {
+		t.Errorf("Should only use prefixed accessors with the option, but got %+v, %v", dto, err)
+	}
+
+	type Record struct {
+		*accessorUser
+		*accessorAudit
+	}
+
+	type RecordDTO struct {
+		Name      string
+		UpdatedBy string
+	}
+
+	var record RecordDTO
+	if err := CopyWithOption(&record, &Record{}, accessors); err != nil {
+		t.Fatalf("Should call getters promoted from nil embedded pointers, but got %v", err)
+	}
+	if record != (RecordDTO{}) {
+		t.Errorf("Should get zero values from nil embedded pointers, but got %+v", record)
+	}
+
+	record = RecordDTO{}
+	if err := CopyWithOption(&record, &Record{&accessorUser{name: "jinzhu"}, &accessorAudit{by: "admin"}}, accessors); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if record.Name != "jinzhu" || record.UpdatedBy != "admin" {
+		t.Errorf("Should copy from promoted getters, but got %+v", record)
+	}
+
+	type TitleDTO struct {
+		Title string
+	}
+
+	var title TitleDTO
+	if err := Copy(&title, &titleOuter{}); err != nil || title.Title != "outer" {
+		t.Errorf("Should call getters shadowing ones of nil embedded pointers, but got %+v, %v", title, err)
+	}
+
+	title = TitleDTO{}
+	if err := Copy(&title, &titleShadowed{}); err != nil || title.Title != "middle" {
+		t.Errorf("Should call getters of shallower embedded types, but got %+v, %v", title, err)
+	}
+
+	title = TitleDTO{}
+	if err := Copy(&title, &titleShadowed{&titleMiddle{&titleBase{}}}); err != nil || title.Title != "middle" {
+		t.Errorf("Should call getters of shallower embedded types, but got %+v, %v", title, err)
+	}
+
+	type BaseRecord struct {
+		*titleBase
+	}
+
+	title = TitleDTO{Title: "unchanged"}
+	if err := Copy(&title, &BaseRecord{}); err != nil || title.Title != "unchanged" {
+		t.Errorf("Should skip getters promoted from nil embedded pointers, but got %+v, %v", title, err)
+	}
+}
+
+var errInvalidEmail = errors.New("invalid email")
+
+type validatedContact struct {
+	email string
+}
+
+func (contact *validatedContact) SetEmail(email string) error {
+	if !strings.Contains(email, "@") {
+		return errInvalidEmail
+	}
+	contact.email = email
+	return nil
+}
+
+type sealedContact struct {
+	Email string
+}
+
+func (contact sealedContact) Secret() (string, error) {
+	if contact.Email == "" {
+		return "", errInvalidEmail
+	}
+	return "secret", nil
+}
+
+func TestCopyAccessorErrors(t *testing.T) {
+	type ContactDTO struct {
+		Email string
+	}
+
+	var contact validatedContact
+	if err := CopyWithOption(&contact, &ContactDTO{Email: "jinzhu@example.org"}, Option{SetterPrefix: "Set"}); err != nil || contact.email != "jinzhu@example.org" {
+		t.Errorf("Should copy to setters returning errors, but got %+v, %v", contact, err)
+	}
+
+	var contacts []validatedContact
+	err := CopyWithOption(&contacts, []ContactDTO{{Email: "jinzhu@example.org"}, {Email: "jinzhu"}}, Option{SetterPrefix: "Set"})
+	if !errors.Is(err, errInvalidEmail) || err.Error() != "copy [1].Email: invalid email" {
+		t.Errorf("Should return the setter error with its path, but got %v", err)
+	}
+
+	type SecretDTO struct {
+		Secret string
+	}
+
+	var secret SecretDTO
+	if err := Copy(&secret, &sealedContact{Email: "jinzhu@example.org"}); err != nil || secret.Secret != "secret" {
+		t.Errorf("Should copy from getters returning errors, but got %+v, %v", secret, err)
+	}
+
+	type Wrapper struct {
+		Contact SecretDTO
+	}
+
+	type Source struct {
+		Contact sealedContact
+	}
+
+	err = Copy(&Wrapper{}, &Source{})
+	if !errors.Is(err, errInvalidEmail) || err.Error() != "copy Contact.Secret: invalid email" {
+		t.Errorf("Should return the getter error with its path, but got %v", err)
+	}
+}
+
+type methodPerson struct {
+	first, last string
+}
+
+func (person methodPerson) FullName() string {
+	return person.first + " " + person.last
+}
+
+func (person *methodPerson) GetAge() (int, error) {
+	if person.first == "" {
+		return 0, errInvalidEmail
+	}
+	return 18, nil
+}
+
+type methodProfile struct {
+	fullName string
+	age      int
+}
+
+func (profile *methodProfile) FullName(fullName string) {
+	profile.fullName = fullName
+}
+
+func (profile *methodProfile) SetAge(age int) error {
+	profile.age = age
+	return nil
+}
+
+func TestCopyMethodToMethod(t *testing.T) {
+	var profile methodProfile
+	if err := CopyWithOption(&profile, &methodPerson{first: "Jinzhu", last: "Zhang"}, Option{GetterPrefix: "Get", SetterPrefix: "Set"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if profile.fullName != "Jinzhu Zhang" || profile.age != 18 {
+		t.Errorf("Should copy from getters to setters, but got %+v", profile)
+	}
+
+	profile = methodProfile{}
+	if err := Copy(&profile, &methodPerson{first: "Jinzhu", last: "Zhang"}); err != nil || profile.fullName != "Jinzhu Zhang" || profile.age != 0 {
+		t.Errorf("Should only use prefixed accessors with the option, but got %+v, %v", profile, err)
+	}
+
+	err := CopyWithOption(&profile, &methodPerson{}, Option{GetterPrefix: "Get", SetterPrefix: "Set"})
+	if !errors.Is(err, errInvalidEmail) || err.Error() != "copy Age: invalid email" {
+		t.Errorf("Should return the getter error with its path, but got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Package copiertest provides helpers to lock down copier mappings in tests.
+package copiertest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/smw-104/copier"
+)
+
+// UpdateEnv is the environment variable that makes snapshots be written, like
+// the -update flag of tests that define one
+const UpdateEnv = "COPIERTEST_UPDATE"
+
+// updating reports whether golden files are written. copiertest doesn't
+// define the -update flag, so test packages defining their own don't panic,
+// but it uses the flag when they do.
+func updating() bool {
+	if f := flag.Lookup("update"); f != nil {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			if update, ok := getter.Get().(bool); ok && update {
+				return true
+			}
+		}
+	}
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return update
+}
+
+// AssertMappingSnapshot compares how copier copies fromValue to toValue with the
+// snapshot in testdata/<test name>.golden, run tests with -update, or with
+// COPIERTEST_UPDATE=1 when the test package has no -update flag, to write it
+func AssertMappingSnapshot(t testing.TB, toValue interface{}, fromValue interface{}) bool {
+	t.Helper()
+	return AssertMappingSnapshotWithOption(t, toValue, fromValue, copier.Option{})
+}
+
+// AssertMappingSnapshotWithOption assert mapping snapshot with option
+func AssertMappingSnapshotWithOption(t testing.TB, toValue interface{}, fromValue interface{}, opt copier.Option) bool {
+	t.Helper()
+
+	plan, err := copier.ExplainWithOption(toValue, fromValue, opt)
+	if err != nil {
+		t.Errorf("explain mapping: %v", err)
+		return false
+	}
+
+	actual := plan.String()
+	golden := goldenFile(t)
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Errorf("update mapping snapshot: %v", err)
+			return false
+		}
+		if err := ioutil.WriteFile(golden, []byte(actual), 0644); err != nil {
+			t.Errorf("update mapping snapshot: %v", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Errorf("read mapping snapshot: %v, run tests with -update or %v=1 to create it", err, UpdateEnv)
+		return false
+	}
+
+	if string(expected) != actual {
+		t.Errorf("mapping from %v to %v changed, run tests with -update or %v=1 if this is expected\n%v", plan.From, plan.To, UpdateEnv, diff(string(expected), actual))
+		return false
+	}
+	return true
+}
+
+// goldenFile returns the snapshot file of the running test
+func goldenFile(t testing.TB) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_").Replace(t.Name())
+	return filepath.Join("testdata", name+".golden")
+}
+
+// diff returns the lines removed from expected and added to actual
+func diff(expected, actual string) string {
+	var (
+		lines         []string
+		expectedLines = strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+		actualLines   = strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+	)
+
+	// longest common subsequence of lines
+	lcs := make([][]int, len(expectedLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(actualLines)+1)
+	}
+	for i := len(expectedLines) - 1; i >= 0; i-- {
+		for j := len(actualLines) - 1; j >= 0; j-- {
+			if expectedLines[i] == actualLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(expectedLines) || j < len(actualLines) {
+		switch {
+		case i < len(expectedLines) && j < len(actualLines) && expectedLines[i] == actualLines[j]:
+			lines = append(lines, "  "+expectedLines[i])
+			i++
+			j++
+		case j < len(actualLines) && (i == len(expectedLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			lines = append(lines, "+ "+actualLines[j])
+			j++
+		default:
+			lines = append(lines, "- "+expectedLines[i])
+			i++
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,114 @@
+package copiertest
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// update is the usual golden file flag of test packages, defining it must not
+// conflict with copiertest
+var update = flag.Bool("update", false, "update golden files")
+
+type User struct {
+	Name  string
+	Role  string
+	Age   int32
+	Email string
+}
+
+func (user *User) DoubleAge() int32 {
+	return 2 * user.Age
+}
+
+type Employee struct {
+	Name      string
+	Age       int64
+	DoubleAge int32
+	EmployeId int64
+	SuperRule string
+}
+
+func (employee *Employee) Role(role string) {
+	employee.SuperRule = "Super " + role
+}
+
+type Employee2 struct {
+	Name      string
+	DoubleAge int32
+}
+
+// recorder records the errors of a test
+type recorder struct {
+	testing.TB
+	name   string
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Name() string {
+	return r.name
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertMappingSnapshot(t *testing.T) {
+	AssertMappingSnapshot(t, &Employee{}, &User{})
+}
+
+func TestAssertMappingSnapshotChanged(t *testing.T) {
+	if updating() {
+		t.Skip("snapshots are written with -update")
+	}
+
+	r := &recorder{TB: t, name: "TestAssertMappingSnapshot"}
+	if AssertMappingSnapshot(r, &Employee2{}, &User{}) {
+		t.Fatalf("Should fail when the mapping changed")
+	}
+
+	if len(r.errors) != 1 {
+		t.Fatalf("Should report one error, but got %v", r.errors)
+	}
+	for _, line := range []string{"- Age        Age          convert", "- Role()     Role         assign", "unused source fields: Role, Age, Email"} {
+		if !strings.Contains(r.errors[0], line) {
+			t.Errorf("Error should contain %q, but got\n%v", line, r.errors[0])
+		}
+	}
+}
+
+func TestAssertMappingSnapshotMissing(t *testing.T) {
+	if updating() {
+		t.Skip("snapshots are written with -update")
+	}
+
+	r := &recorder{TB: t, name: "TestMissing"}
+	if AssertMappingSnapshot(r, &Employee{}, &User{}) || len(r.errors) != 1 || !strings.Contains(r.errors[0], "-update") {
+		t.Errorf("Should fail without a snapshot, but got %v", r.errors)
+	}
+}
+
+func TestUpdating(t *testing.T) {
+	if updating() {
+		t.Skip("snapshots are written with -update")
+	}
+
+	if updating() {
+		t.Errorf("Should not update snapshots by default")
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	if !updating() {
+		t.Errorf("Should update snapshots with %v", UpdateEnv)
+	}
+	t.Setenv(UpdateEnv, "")
+
+	flag.Set("update", "true")
+	defer flag.Set("update", "false")
+	if !updating() {
+		t.Errorf("Should update snapshots with the -update flag of the test package")
+	}
+}
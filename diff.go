@@ -0,0 +1,124 @@
+package copier
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Change is a destination field whose value was changed by a copy
+type Change struct {
+	Path string      // path of the field, as given to Option.OnField
+	Old  interface{} // value before the copy
+	New  interface{} // value after the copy
+}
+
+// Changes are the changed destination fields, in copy order
+type Changes []Change
+
+// Paths returns the paths of the changed fields
+func (changes Changes) Paths() []string {
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	return paths
+}
+
+// CopyAndDiff copies from to to like Copy, and returns the destination fields
+// whose values changed, including the fields setters changed. Fields set to
+// the value they had aren't included.
+func CopyAndDiff(toValue interface{}, fromValue interface{}) (Changes, error) {
+	return CopyAndDiffWithOption(toValue, fromValue, Option{})
+}
+
+// CopyAndDiffWithOption is CopyAndDiff with options, opt.OnField is still called
+func CopyAndDiffWithOption(toValue interface{}, fromValue interface{}, opt Option) (Changes, error) {
+	var (
+		written []Change
+		indexes = map[string]int{}
+		onField = opt.OnField
+	)
+
+	// a field written twice keeps its first old value
+	opt.OnField = func(path string, from, to reflect.Value, oldTo interface{}) {
+		if i, ok := indexes[path]; ok {
+			written[i].New = snapshot(to)
+		} else {
+			indexes[path] = len(written)
+			written = append(written, Change{Path: path, Old: oldTo, New: snapshot(to)})
+		}
+		if onField != nil {
+			onField(path, from, to, oldTo)
+		}
+	}
+
+	err := copier(toValue, fromValue, opt)
+
+	var changes Changes
+	for _, change := range written {
+		if !reflect.DeepEqual(change.Old, change.New) {
+			changes = append(changes, change)
+		}
+	}
+	return changes, err
+}
+
+// Diff returns the fields of a that copying b into it would change, without
+// changing a. The types of a and b may differ, fields are matched, read and
+// converted like Copy does.
+func Diff(a, b interface{}) (Changes, error) {
+	return DiffWithOption(a, b, Option{})
+}
+
+// DiffWithOption is Diff with options
+func DiffWithOption(a, b interface{}, opt Option) (Changes, error) {
+	value := indirect(reflect.ValueOf(a))
+	if !value.IsValid() {
+		return nil, errors.New("diff with nil value")
+	}
+
+	clone := reflect.New(value.Type())
+	clone.Elem().Set(deepCopy(value))
+	return CopyAndDiffWithOption(clone.Interface(), b, opt)
+}
+
+// deepCopy returns a copy of v that shares no pointers, slices or maps with
+// it, unexported fields are copied shallowly
+func deepCopy(v reflect.Value) reflect.Value {
+	copied := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			elem := reflect.New(v.Type().Elem())
+			elem.Elem().Set(deepCopy(v.Elem()))
+			copied.Set(elem)
+		}
+	case reflect.Struct:
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopy(v.Field(i)))
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			slice := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				slice.Index(i).Set(deepCopy(v.Index(i)))
+			}
+			copied.Set(slice)
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				m.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			copied.Set(m)
+		}
+	default:
+		copied.Set(v)
+	}
+	return copied
+}
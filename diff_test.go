@@ -0,0 +1,173 @@
+package copier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCopyAndDiff(t *testing.T) {
+	type Address struct {
+		City, Zip string
+	}
+
+	type Order struct {
+		ID      int64
+		Status  string
+		Count   *int
+		Address Address
+	}
+
+	type OrderUpdate struct {
+		Status  string
+		Count   int
+		Address struct{ City string }
+	}
+
+	count := 2
+	order := Order{ID: 1, Status: "pending", Count: &count, Address: Address{City: "Shanghai", Zip: "200000"}}
+
+	update := OrderUpdate{Status: "shipped", Count: 2}
+	update.Address.City = "Beijing"
+
+	changes, err := CopyAndDiff(&order, &update)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	if paths := changes.Paths(); !reflect.DeepEqual(paths, []string{"Status", "Address.City"}) {
+		t.Fatalf("Should only return the changed fields, but got %v", paths)
+	}
+	if changes[0].Old != "pending" || changes[0].New != "shipped" {
+		t.Errorf("Should return the old and new values, but got %+v", changes[0])
+	}
+	if order.Status != "shipped" || order.Address.City != "Beijing" || order.Address.Zip != "200000" {
+		t.Errorf("Should copy the fields, but got %+v", order)
+	}
+
+	if changes, err := CopyAndDiff(&order, &update); err != nil || len(changes) != 0 {
+		t.Errorf("Should return no changes copying again, but got %v, %v", changes, err)
+	}
+}
+
+func TestCopyAndDiffPointer(t *testing.T) {
+	type Model struct {
+		Count *int
+	}
+
+	type Update struct {
+		Count int
+	}
+
+	count := 1
+	model := Model{Count: &count}
+
+	changes, err := CopyAndDiff(&model, &Update{Count: 3})
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("Should return the changed pointer field, but got %v, %v", changes, err)
+	}
+	if old, new := changes[0].Old.(*int), changes[0].New.(*int); *old != 1 || *new != 3 {
+		t.Errorf("Should keep the old value written through the pointer, but got %v -> %v", *old, *new)
+	}
+}
+
+func TestCopyAndDiffSetters(t *testing.T) {
+	nickname := ""
+	employee := Employee{Name: "Jinzhu", Nickname: &nickname, SuperRule: "Super Dev"}
+
+	changes, err := CopyAndDiff(&employee, &User{Name: "Jinzhu", Role: "Admin"})
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if paths := changes.Paths(); !reflect.DeepEqual(paths, []string{"SuperRule"}) {
+		t.Fatalf("Should return the fields changed by setters, but got %v", paths)
+	}
+	if changes[0].Old != "Super Dev" || changes[0].New != "Super Admin" {
+		t.Errorf("Should return the old and new values, but got %+v", changes[0])
+	}
+
+	if changes, err := CopyAndDiff(&employee, &User{Name: "Jinzhu", Role: "Admin"}); err != nil || len(changes) != 0 {
+		t.Errorf("Should not return fields setters set to the value they had, but got %v, %v", changes, err)
+	}
+}
+
+func TestCopyAndDiffWithOption(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	type UserDTO struct {
+		FullName string `json:"name"`
+	}
+
+	var (
+		user  = User{Name: "jinzhu"}
+		calls int
+	)
+
+	changes, err := CopyAndDiffWithOption(&user, &UserDTO{FullName: "Jinzhu"}, Option{
+		TagMatch: "json",
+		OnField:  func(string, reflect.Value, reflect.Value, interface{}) { calls++ },
+	})
+	if err != nil || len(changes) != 1 || changes[0].Path != "Name" {
+		t.Errorf("Should return the changes, but got %v, %v", changes, err)
+	}
+	if calls != 1 {
+		t.Errorf("Should still call OnField, but got %v calls", calls)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	var fakeAge int32 = 12
+	nickname := "jinzhu"
+	user := User{Name: "Jinzhu", Nickname: nickname, Age: 18, FakeAge: &fakeAge, Role: "Admin", Notes: []string{"hello"}}
+	employee := Employee{Name: "Jinzhu", Nickname: &nickname, Age: 20, FakeAge: 12, DoubleAge: 36, SuperRule: "Super Admin", Notes: []string{"hello"}}
+
+	changes, err := Diff(&employee, user)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if paths := changes.Paths(); !reflect.DeepEqual(paths, []string{"Age"}) {
+		t.Fatalf("Should only return the out of sync fields, but got %v", paths)
+	}
+	if changes[0].Old != int64(20) || changes[0].New != int64(18) {
+		t.Errorf("Should return the values before and after copying, but got %+v", changes[0])
+	}
+	if employee.Age != 20 {
+		t.Errorf("Should not change a, but got %v", employee.Age)
+	}
+}
+
+func TestDiffDoesNotChangeA(t *testing.T) {
+	type Inner struct {
+		Value string
+	}
+
+	type Model struct {
+		Inner *Inner
+		Tags  []string
+	}
+
+	type DTO struct {
+		Inner struct{ Value string }
+		Tags  []string
+	}
+
+	model := Model{Inner: &Inner{Value: "old"}, Tags: []string{"a"}}
+	dto := DTO{Tags: []string{"b"}}
+	dto.Inner.Value = "new"
+
+	changes, err := Diff(model, &dto)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if paths := changes.Paths(); !reflect.DeepEqual(paths, []string{"Inner", "Tags"}) {
+		t.Errorf("Should return the changed fields, but got %v", paths)
+	}
+	if model.Inner.Value != "old" || model.Tags[0] != "a" {
+		t.Errorf("Should not change a through its pointers, but got %+v, %v", model.Inner, model.Tags)
+	}
+
+	if _, err := Diff(nil, &dto); err == nil {
+		t.Errorf("Should not diff nil")
+	}
+}
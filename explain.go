@@ -0,0 +1,71 @@
+package copier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+// Conversion is how a value is copied from its source to its destination
+type Conversion = mapping.Conversion
+
+// Conversions used by Copy
+const (
+	ConversionNone      = mapping.ConversionNone      // the value can't be copied
+	ConversionAssign    = mapping.ConversionAssign    // the value is assigned
+	ConversionConvert   = mapping.ConversionConvert   // the value is converted to the destination type
+	ConversionScanner   = mapping.ConversionScanner   // the destination scans the value with sql.Scanner
+	ConversionValuer    = mapping.ConversionValuer    // the value is read with driver.Valuer
+	ConversionRecursive = mapping.ConversionRecursive // the value is copied with Copy
+	ConversionFunc      = mapping.ConversionFunc      // the value is returned by a func
+)
+
+// MemberKind is the kind of struct member a value is copied from or to
+type MemberKind = mapping.MemberKind
+
+// Member kinds
+const (
+	MemberNone   = mapping.MemberNone
+	MemberField  = mapping.MemberField
+	MemberMethod = mapping.MemberMethod
+	MemberFunc   = mapping.MemberFunc
+)
+
+// Plan describes what Copy does when copying between two struct types
+type Plan = mapping.Plan
+
+// FieldPlan describes how a destination field or setter gets its value
+type FieldPlan = mapping.FieldPlan
+
+// Explain describes how Copy copies fromValue to toValue, both can be values
+// or reflect.Type of structs, or pointers or slices of them
+func Explain(toValue interface{}, fromValue interface{}) (*Plan, error) {
+	return ExplainWithOption(toValue, fromValue, Option{})
+}
+
+// ExplainWithOption explain with option
+func ExplainWithOption(toValue interface{}, fromValue interface{}, opt Option) (*Plan, error) {
+	toType, fromType := explainType(toValue), explainType(fromValue)
+	if toType == nil || fromType == nil {
+		return nil, errors.New("explain needs two types")
+	}
+
+	if toType.Kind() != reflect.Struct || fromType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("explain copy from %v to %v: both types must be structs", fromType, toType)
+	}
+
+	return mapTypes(toType, fromType, opt).Plan(), nil
+}
+
+func explainType(value interface{}) reflect.Type {
+	reflectType, ok := value.(reflect.Type)
+	if !ok {
+		reflectType = reflect.TypeOf(value)
+	}
+	if reflectType == nil {
+		return nil
+	}
+	return indirectType(reflectType)
+}
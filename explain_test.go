@@ -0,0 +1,88 @@
+package copier
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExplain(t *testing.T) {
+	plan, err := Explain(&Employee{}, &User{})
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	expected := []FieldPlan{
+		{Dest: "Name", DestKind: MemberField, Source: "Name", SourceKind: MemberField, Conversion: ConversionAssign},
+		{Dest: "Birthday", DestKind: MemberField, Source: "Birthday", SourceKind: MemberField, Conversion: ConversionAssign},
+		{Dest: "Nickname", DestKind: MemberField, Source: "Nickname", SourceKind: MemberField, Conversion: ConversionConvert},
+		{Dest: "Age", DestKind: MemberField, Source: "Age", SourceKind: MemberField, Conversion: ConversionConvert},
+		{Dest: "FakeAge", DestKind: MemberField, Source: "FakeAge", SourceKind: MemberField, Conversion: ConversionConvert},
+		{Dest: "EmployeID", DestKind: MemberField, SourceKind: MemberNone, Conversion: ConversionNone},
+		{Dest: "DoubleAge", DestKind: MemberField, Source: "DoubleAge", SourceKind: MemberMethod, Conversion: ConversionAssign},
+		{Dest: "SuperRule", DestKind: MemberField, SourceKind: MemberNone, Conversion: ConversionNone},
+		{Dest: "Notes", DestKind: MemberField, Source: "Notes", SourceKind: MemberField, Conversion: ConversionAssign},
+		{Dest: "Role", DestKind: MemberMethod, Source: "Role", SourceKind: MemberField, Conversion: ConversionAssign},
+	}
+	if !reflect.DeepEqual(plan.Fields, expected) {
+		t.Errorf("Unexpected fields %#v", plan.Fields)
+	}
+	if plan.UnusedSource != nil || plan.UnusedSetters != nil {
+		t.Errorf("Should not have unused source fields or setters, but got %v and %v", plan.UnusedSource, plan.UnusedSetters)
+	}
+
+	plan, err = Explain(reflect.TypeOf([]TypeStruct3{}), reflect.TypeOf(TypeStruct1{}))
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	for _, field := range plan.Fields {
+		if field.Dest == "Field5" && field.Conversion != ConversionRecursive {
+			t.Errorf("Field5 should be copied recursively, but got %v", field.Conversion)
+		}
+	}
+
+	type Extra struct {
+		Name  string
+		Phone string
+	}
+	plan, _ = Explain(&Employee{}, &Extra{})
+	plan.UnusedSource[0] = "Changed"
+	if plan, _ = Explain(&Employee{}, &Extra{}); !reflect.DeepEqual(plan.UnusedSource, []string{"Phone"}) {
+		t.Errorf("Should not be changed by changing a previous plan, but got %v", plan.UnusedSource)
+	}
+
+	if _, err := Explain(&Employee{}, "str"); err == nil {
+		t.Errorf("Should raise error when explaining non struct types")
+	}
+}
+
+func TestExplainString(t *testing.T) {
+	type Model struct {
+		A       string
+		B       int64
+		C       time.Time
+		Employe int64
+	}
+
+	type DTO struct {
+		A         string
+		B         time.Time
+		EmployeId int64
+	}
+
+	plan, err := Explain(&DTO{}, &Model{})
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	expected := `copier.Model -> copier.DTO
+DEST       SOURCE  CONVERSION  WARNINGS
+A          A       assign
+B          -       none        can't copy B from int64 to time.Time
+EmployeId  -       none        did you mean Employe?
+unused source fields: B, C, Employe
+`
+	if plan.String() != expected {
+		t.Errorf("Unexpected plan\n%v", plan)
+	}
+}
@@ -0,0 +1,57 @@
+package copier
+
+import (
+	"reflect"
+)
+
+// CopyTo copies from to a new value of type To, pointer types are allocated
+func CopyTo[To any, From any](from From) (To, error) {
+	var to To
+	if isNil(from) {
+		return to, nil
+	}
+
+	to = newValue[To]()
+	err := Copy(&to, from)
+	return to, err
+}
+
+// CopySlice copies every element of from to a new slice of To
+func CopySlice[To any, From any](from []From) ([]To, error) {
+	if from == nil {
+		return nil, nil
+	}
+
+	to := make([]To, len(from))
+	for i, elem := range from {
+		var err error
+		if to[i], err = CopyTo[To](elem); err != nil {
+			return nil, err
+		}
+	}
+	return to, nil
+}
+
+// Clone returns a deep copy of value sharing no pointers, slices or maps with
+// it. Unexported fields are copied as they are.
+func Clone[T any](value T) T {
+	if !reflect.ValueOf(value).IsValid() {
+		return value
+	}
+	return deepCopy(reflect.ValueOf(value)).Interface().(T)
+}
+
+// newValue returns the zero value of T, pointer types are allocated
+func newValue[T any]() T {
+	var value T
+	if valueType := reflect.TypeOf(&value).Elem(); valueType.Kind() == reflect.Ptr {
+		value = reflect.New(valueType.Elem()).Interface().(T)
+	}
+	return value
+}
+
+// isNil reports whether value is nil or a nil pointer, slice or map
+func isNil(value interface{}) bool {
+	reflectValue := reflect.ValueOf(value)
+	return !reflectValue.IsValid() || (isNilable(reflectValue) && reflectValue.IsNil())
+}
@@ -0,0 +1,151 @@
+package copier
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCopyTo(t *testing.T) {
+	var fakeAge int32 = 12
+	user := User{Name: "Jinzhu", Nickname: "jinzhu", Age: 18, FakeAge: &fakeAge, Role: "Admin", Notes: []string{"hello world"}}
+
+	employee, err := CopyTo[Employee](user)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	checkEmployee(employee, user, t, "CopyTo Struct")
+
+	employee2, err := CopyTo[*Employee](&user)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	checkEmployee(*employee2, user, t, "CopyTo Ptr")
+
+	employee3, err := CopyTo[*Employee]((*User)(nil))
+	if err != nil || employee3 != nil {
+		t.Errorf("Should copy nil to nil, but got %v, %v", employee3, err)
+	}
+}
+
+func TestCopySlice(t *testing.T) {
+	users := []*User{{Name: "Jinzhu", Age: 18, Role: "Admin", Notes: []string{"hello world"}}, nil, {Name: "Jinzhu2", Age: 22, Role: "Dev"}}
+
+	employees, err := CopySlice[Employee](users)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if len(employees) != 3 {
+		t.Fatalf("Should have three elems, but got %v", len(employees))
+	}
+	checkEmployee2(employees[0], users[0], t, "CopySlice @ 1")
+	checkEmployee2(employees[1], users[1], t, "CopySlice @ 2")
+	checkEmployee2(employees[2], users[2], t, "CopySlice @ 3")
+
+	employees2, err := CopySlice[*Employee](users)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if employees2[1] != nil {
+		t.Errorf("Nil elems should be copied to nil")
+	}
+	checkEmployee(*employees2[2], *users[2], t, "CopySlice Ptr @ 3")
+
+	if employees3, err := CopySlice[Employee]([]User(nil)); employees3 != nil || err != nil {
+		t.Errorf("Should copy nil slice to nil, but got %v, %v", employees3, err)
+	}
+}
+
+func TestClone(t *testing.T) {
+	var fakeAge int32 = 12
+	user := &User{Name: "Jinzhu", Age: 18, FakeAge: &fakeAge, Notes: []string{"hello world"}}
+
+	clone := Clone(user)
+	if clone == user || clone.FakeAge == user.FakeAge {
+		t.Errorf("Clone should not share pointers")
+	}
+	if clone.Name != user.Name || *clone.FakeAge != *user.FakeAge || clone.Notes[0] != user.Notes[0] {
+		t.Errorf("Clone should have the same values, but got %+v", clone)
+	}
+	clone.Notes[0] = "changed"
+	if user.Notes[0] != "hello world" {
+		t.Errorf("Clone should not share slices, but got %v", user.Notes)
+	}
+
+	if users := Clone([]User{*user}); len(users) != 1 || users[0].Name != user.Name {
+		t.Errorf("Clone should copy slices, but got %+v", users)
+	}
+
+	ints := []int{1, 2}
+	intsClone := Clone(ints)
+	if !reflect.DeepEqual(intsClone, ints) {
+		t.Fatalf("Clone should copy slices of values, but got %v", intsClone)
+	}
+	intsClone[0] = 3
+	if ints[0] != 1 {
+		t.Errorf("Clone should not share slices, but got %v", ints)
+	}
+
+	type Labeled struct {
+		Labels map[string]string
+		Tags   []string
+	}
+	labeled := Labeled{Labels: map[string]string{"env": "prod"}, Tags: []string{"a"}}
+	labeledClone := Clone(labeled)
+	if !reflect.DeepEqual(labeledClone, labeled) {
+		t.Fatalf("Clone should copy maps, but got %+v", labeledClone)
+	}
+	labeledClone.Labels["env"] = "dev"
+	labeledClone.Tags[0] = "b"
+	if labeled.Labels["env"] != "prod" || labeled.Tags[0] != "a" {
+		t.Errorf("Clone should not share maps or slices, but got %+v", labeled)
+	}
+
+	if nilClone := Clone((*User)(nil)); nilClone != nil {
+		t.Errorf("Clone of nil should be nil, but got %v", nilClone)
+	}
+	if value := Clone(42); value != 42 {
+		t.Errorf("Clone should copy values, but got %v", value)
+	}
+	if value := Clone[interface{}](nil); value != nil {
+		t.Errorf("Clone of a nil interface should be nil, but got %v", value)
+	}
+}
+
+type cloneAudit struct {
+	ID int64
+}
+
+type cloneOwner struct {
+	ID int64
+}
+
+type cloneAccount struct {
+	Name    string
+	balance int
+}
+
+func TestCloneUnexportedFields(t *testing.T) {
+	account := cloneAccount{Name: "x", balance: 5}
+	if clone := Clone(account); clone != account {
+		t.Errorf("Clone should keep unexported fields, but got %+v", clone)
+	}
+	if clone := Clone(&account); clone == &account || *clone != account {
+		t.Errorf("Clone should keep unexported fields behind pointers, but got %+v", clone)
+	}
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	if clone := Clone(created); !clone.Equal(created) {
+		t.Errorf("Clone should copy time.Time, but got %v", clone)
+	}
+
+	type Ambiguous struct {
+		cloneAudit
+		cloneOwner
+		Name string
+	}
+	ambiguous := Ambiguous{cloneAudit{1}, cloneOwner{2}, "x"}
+	if clone := Clone(ambiguous); clone != ambiguous {
+		t.Errorf("Clone should copy structs with ambiguous fields as they are, but got %+v", clone)
+	}
+}
@@ -0,0 +1,15 @@
+package copier
+
+// BeforeCopier is implemented by destinations that are called before their
+// fields are copied, from is a pointer to the source struct. Returning an
+// error stops the copy.
+type BeforeCopier interface {
+	BeforeCopy(from interface{}) error
+}
+
+// AfterCopier is implemented by destinations that are called after their
+// fields are copied, from is a pointer to the source struct. Returning an
+// error stops the copy.
+type AfterCopier interface {
+	AfterCopy(from interface{}) error
+}
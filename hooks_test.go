@@ -0,0 +1,66 @@
+package copier
+
+import (
+	"errors"
+	"testing"
+)
+
+type hookSource struct {
+	First, Last string
+}
+
+type hookDest struct {
+	First, Last string
+	FullName    string
+	Calls       []string
+}
+
+var errNoName = errors.New("no first name")
+
+func (dest *hookDest) BeforeCopy(from interface{}) error {
+	source := from.(*hookSource)
+	if source.First == "" {
+		return errNoName
+	}
+	dest.Calls = append(dest.Calls, "before "+dest.First)
+	return nil
+}
+
+func (dest *hookDest) AfterCopy(from interface{}) error {
+	dest.FullName = dest.First + " " + dest.Last
+	dest.Calls = append(dest.Calls, "after "+dest.First)
+	return nil
+}
+
+func TestCopyHooks(t *testing.T) {
+	var dest hookDest
+	if err := Copy(&dest, hookSource{First: "Jinzhu", Last: "Zhang"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if dest.FullName != "Jinzhu Zhang" {
+		t.Errorf("AfterCopy should set FullName, but got %q", dest.FullName)
+	}
+	if len(dest.Calls) != 2 || dest.Calls[0] != "before " || dest.Calls[1] != "after Jinzhu" {
+		t.Errorf("Hooks should be called around the fields, but got %v", dest.Calls)
+	}
+
+	if err := Copy(&dest, &hookSource{Last: "Zhang"}); err != errNoName {
+		t.Errorf("Should return the BeforeCopy error, but got %v", err)
+	}
+}
+
+func TestCopyHooksSlice(t *testing.T) {
+	var dests []hookDest
+	sources := []hookSource{{First: "Jinzhu", Last: "Zhang"}, {First: "Tom", Last: "Smith"}}
+	if err := Copy(&dests, sources); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if len(dests) != 2 || dests[0].FullName != "Jinzhu Zhang" || dests[1].FullName != "Tom Smith" {
+		t.Errorf("Hooks should be called per element, but got %+v", dests)
+	}
+
+	sources = append(sources, hookSource{Last: "Doe"})
+	if err := Copy(&[]*hookDest{}, sources); err != errNoName {
+		t.Errorf("Should return the BeforeCopy error of an element, but got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package gotypes implements the mapping Type of go/types types for static
+// tools, apart from the mapping package so Copy doesn't link go/types.
+package gotypes
+
+import (
+	"go/types"
+	"reflect"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+// goType is a Type implemented by go/types
+type goType struct {
+	types.Type
+}
+
+// Of returns the mapping Type of a go/types type
+func Of(t types.Type) mapping.Type {
+	return goType{t}
+}
+
+func (t goType) String() string {
+	return types.TypeString(t.Type, func(pkg *types.Package) string {
+		return pkg.Name()
+	})
+}
+
+func (t goType) Kind() mapping.Kind {
+	switch t.Underlying().(type) {
+	case *types.Struct:
+		return mapping.Struct
+	case *types.Pointer:
+		return mapping.Ptr
+	case *types.Slice:
+		return mapping.Slice
+	case *types.Interface:
+		return mapping.Interface
+	}
+	return mapping.Other
+}
+
+func (t goType) Elem() mapping.Type {
+	switch underlying := t.Underlying().(type) {
+	case *types.Pointer:
+		return goType{underlying.Elem()}
+	case *types.Slice:
+		return goType{underlying.Elem()}
+	}
+	return nil
+}
+
+func (t goType) Fields() []mapping.Field {
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	fields := make([]mapping.Field, structType.NumFields())
+	for i := range fields {
+		field := structType.Field(i)
+		fields[i] = mapping.Field{
+			Name:      field.Name(),
+			Exported:  field.Exported(),
+			Anonymous: field.Embedded(),
+			Tag:       reflect.StructTag(structType.Tag(i)),
+			Type:      goType{field.Type()},
+		}
+	}
+	return fields
+}
+
+func (t goType) PtrMethods() []mapping.Method {
+	var (
+		methodSet = types.NewMethodSet(types.NewPointer(t.Type))
+		methods   []mapping.Method
+	)
+
+	for i := 0; i < methodSet.Len(); i++ {
+		fn, ok := methodSet.At(i).Obj().(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+
+		signature := fn.Type().(*types.Signature)
+		method := mapping.Method{Name: fn.Name()}
+		for j := 0; j < signature.Params().Len(); j++ {
+			method.In = append(method.In, goType{signature.Params().At(j).Type()})
+		}
+		for j := 0; j < signature.Results().Len(); j++ {
+			method.Out = append(method.Out, goType{signature.Results().At(j).Type()})
+		}
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+func (t goType) AssignableTo(u mapping.Type) bool {
+	return types.AssignableTo(t.Type, u.(goType).Type)
+}
+
+func (t goType) ConvertibleTo(u mapping.Type) bool {
+	return types.ConvertibleTo(t.Type, u.(goType).Type)
+}
+
+// Nullable reports whether the method set of the type has driver.Valuer's
+// Value() (driver.Value, error)
+func (t goType) Nullable() bool {
+	fn, ok := lookupMethod(t.Type, "Value")
+	if !ok {
+		return false
+	}
+
+	signature := fn.Type().(*types.Signature)
+	if signature.Params().Len() != 0 || signature.Results().Len() != 2 || !isError(signature.Results().At(1).Type()) {
+		return false
+	}
+
+	named, ok := signature.Results().At(0).Type().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "database/sql/driver" && named.Obj().Name() == "Value"
+}
+
+// PtrScanner reports whether a pointer to the type has sql.Scanner's
+// Scan(src interface{}) error
+func (t goType) PtrScanner() bool {
+	fn, ok := lookupMethod(types.NewPointer(t.Type), "Scan")
+	if !ok {
+		return false
+	}
+
+	signature := fn.Type().(*types.Signature)
+	if signature.Params().Len() != 1 || signature.Results().Len() != 1 || !isError(signature.Results().At(0).Type()) {
+		return false
+	}
+
+	param, ok := signature.Params().At(0).Type().Underlying().(*types.Interface)
+	return ok && param.Empty()
+}
+
+func lookupMethod(t types.Type, name string) (*types.Func, bool) {
+	selection := types.NewMethodSet(t).Lookup(nil, name)
+	if selection == nil {
+		return nil, false
+	}
+	fn, ok := selection.Obj().(*types.Func)
+	return fn, ok
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,178 @@
+package gotypes
+
+import (
+	"database/sql"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+const typesSource = `package gotypes
+
+import (
+	"database/sql"
+	"time"
+)
+
+type User struct {
+	Name     string
+	Birthday *time.Time
+	Nickname string ` + "`json:\"nick\"`" + `
+	Role     string
+	Age      int32
+	Income   sql.NullFloat64
+	Address  Address
+	Notes    []string
+	Emial    string
+	Base     Base
+	flags    []byte
+}
+
+func (user User) DoubleAge() int32 {
+	return 2 * user.Age
+}
+
+func (user *User) GetEmail() string {
+	return user.Emial
+}
+
+func (user *User) Balance() (sql.NullInt64, error) {
+	return sql.NullInt64{}, nil
+}
+
+func (user User) Title() string {
+	return "Dr"
+}
+
+type Address struct {
+	City string
+}
+
+type Base struct {
+	ID int64
+}
+
+type Employee struct {
+	Base
+	Name      *string
+	Birthday  time.Time
+	Nick      string ` + "`json:\"nick,omitempty\"`" + `
+	Age       int64
+	Income    *float64
+	Address   *Address
+	Notes     []string
+	DoubleAge int32
+	Email     string
+	Balance   sql.NullInt64
+	SuperRule string
+	City      string ` + "`copier:\"Address.City\"`" + `
+}
+
+func (employee *Employee) Role(role string) {
+	employee.SuperRule = "Super " + role
+}
+
+func (employee *Employee) Reset(all bool) {}
+
+func (employee *Employee) SetTitle(title string) {}
+`
+
+type User struct {
+	Name     string
+	Birthday *time.Time
+	Nickname string `json:"nick"`
+	Role     string
+	Age      int32
+	Income   sql.NullFloat64
+	Address  Address
+	Notes    []string
+	Emial    string
+	Base     Base
+	flags    []byte
+}
+
+func (user User) DoubleAge() int32 {
+	return 2 * user.Age
+}
+
+func (user *User) GetEmail() string {
+	return user.Emial
+}
+
+func (user *User) Balance() (sql.NullInt64, error) {
+	return sql.NullInt64{}, nil
+}
+
+func (user User) Title() string {
+	return "Dr"
+}
+
+type Address struct {
+	City string
+}
+
+type Base struct {
+	ID int64
+}
+
+type Employee struct {
+	Base
+	Name      *string
+	Birthday  time.Time
+	Nick      string `json:"nick,omitempty"`
+	Age       int64
+	Income    *float64
+	Address   *Address
+	Notes     []string
+	DoubleAge int32
+	Email     string
+	Balance   sql.NullInt64
+	SuperRule string
+	City      string `copier:"Address.City"`
+}
+
+func (employee *Employee) Role(role string) {
+	employee.SuperRule = "Super " + role
+}
+
+func (employee *Employee) Reset(all bool) {}
+
+func (employee *Employee) SetTitle(title string) {}
+
+func TestGoTypeMatchesReflect(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", typesSource, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check("gotypes", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []mapping.Options{{}, {TagMatch: "json"}, {GetterPrefix: "Get", SetterPrefix: "Set"}} {
+		for _, pair := range [][2]interface{}{{Employee{}, User{}}, {User{}, Employee{}}} {
+			toType, fromType := reflect.TypeOf(pair[0]), reflect.TypeOf(pair[1])
+			expected := mapping.Resolve(mapping.Reflect(toType), mapping.Reflect(fromType), opts).Plan()
+
+			actual := mapping.Resolve(
+				Of(pkg.Scope().Lookup(toType.Name()).Type()),
+				Of(pkg.Scope().Lookup(fromType.Name()).Type()),
+				opts,
+			).Plan()
+
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("go/types plan with %+v differs from reflect\n%v\n%v", opts, actual, expected)
+			}
+		}
+	}
+}
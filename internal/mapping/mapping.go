@@ -0,0 +1,674 @@
+package mapping
+
+import (
+	"sort"
+	"strings"
+)
+
+// Options are the options of copier.Option that change the mapping
+type Options struct {
+	TagMatch string
+
+	// Unflatten copies source fields to the nested destination fields their
+	// names concatenate, e.g. CustomerName to Customer.Name
+	Unflatten bool
+
+	// GetterPrefix and SetterPrefix name the getters and setters of fields
+	// besides methods named like them, e.g. GetName and SetName for "Get"
+	// and "Set"
+	GetterPrefix string
+	SetterPrefix string
+
+	// Overrides are the per field settings of a copier.Mapper
+	Overrides *Overrides
+}
+
+// Overrides change how destination fields get their values
+type Overrides struct {
+	Ignore  map[string]bool   // destination fields that aren't copied
+	Rename  map[string]string // destination fields copied from another source field
+	Func    map[string]bool   // destination fields set by a func of the source struct
+	Convert map[string]bool   // destination fields set by a func of their source field
+	Skip    map[string]bool   // destination fields that aren't copied, but are still reported as missing
+}
+
+// overridden reports whether the destination field name doesn't get its
+// value by name matching
+func (overrides *Overrides) overridden(name string) bool {
+	return overrides.ignored(name) || overrides.renamed(name) || overrides.skipped(name) || (overrides != nil && overrides.Func[name])
+}
+
+func (overrides *Overrides) ignored(name string) bool {
+	return overrides != nil && overrides.Ignore[name]
+}
+
+func (overrides *Overrides) skipped(name string) bool {
+	return overrides != nil && overrides.Skip[name]
+}
+
+func (overrides *Overrides) renamed(name string) bool {
+	if overrides == nil {
+		return false
+	}
+	_, ok := overrides.Rename[name]
+	return ok
+}
+
+// renamedTo returns the destination fields copied from the source field name, sorted
+func (overrides *Overrides) renamedTo(name string) []string {
+	var names []string
+	if overrides != nil {
+		for to, from := range overrides.Rename {
+			if from == name {
+				names = append(names, to)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldMapping describes where a destination field or setter gets its value from
+type FieldMapping struct {
+	FromField  string // source field name, or dotted path
+	FromMethod string // source getter name
+	ToField    string // destination field name, or dotted path
+	ToMethod   string // destination setter name
+	Conversion Conversion
+}
+
+// Source returns a description of where field copies from
+func (field FieldMapping) Source() string {
+	if field.FromMethod != "" {
+		return field.FromMethod + "()"
+	} else if field.FromField == "" {
+		return "func"
+	}
+	return field.FromField
+}
+
+// Mapping is the resolved mapping from a source struct type to a destination struct type
+type Mapping struct {
+	FromType, ToType Type
+	Fields           []FieldMapping // in copy order
+	ToFields         []string       // exported destination fields, in order, but those copied with their embedded struct
+	UnknownSource    []string       // exported source fields without a target field or setter
+	MissingDest      []string       // exported destination fields without a source field or getter
+	ExcludedDest     []string       // destination fields excluded by tag or ignored
+	Suggestions      map[string][]string
+
+	// AmbiguousSource and AmbiguousDest are field names promoted from several
+	// embedded structs at the same depth, which Go can't select, that the
+	// other type has a field or method for
+	AmbiguousSource []string
+	AmbiguousDest   []string
+}
+
+// Resolve resolves which source fields and getters are copied to which
+// destination fields and setters, fields are copied before getters
+func Resolve(toType, fromType Type, opts Options) *Mapping {
+	var (
+		mapping      = &Mapping{FromType: fromType, ToType: toType}
+		toFieldNames = fieldNamesByTag(toType, opts.TagMatch)
+		overrides    = opts.Overrides
+		mappedDest   = map[string]bool{}
+		seen         = map[string]bool{}
+
+		// fields tagged with a path of the other type, or flattened, are copied through it
+		fromPaths  = tagPaths(toType, fromType)
+		toPaths    = tagPaths(fromType, toType)
+		usedSource = map[string]bool{}
+	)
+	matched := map[string]bool{}
+	for _, field := range deepFields(fromType) {
+		if toName, ok := toFieldNames.match(field); ok {
+			matched[toName] = true
+		}
+	}
+	flattenPaths(fromPaths, toType, fromType, func(name string) bool {
+		_, hasGetter := getter(fromType, name, opts.GetterPrefix)
+		return matched[name] || toFieldNames.excluded[name] || overrides.overridden(name) || hasGetter
+	})
+	if opts.Unflatten {
+		flattenPaths(toPaths, fromType, toType, func(name string) bool {
+			_, hasSetter := setter(toType, name, opts.SetterPrefix, nil)
+			return hasSetter
+		})
+	}
+	for _, path := range fromPaths {
+		usedSource[strings.Split(path, ".")[0]] = true
+	}
+
+	// Copy from field to field or method
+	copiedWhole := map[string]bool{}
+	for _, field := range resolveFields(fromType, toType) {
+		name := field.Name
+		if !field.Exported || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if _, ok := FieldByName(fromType, name); !ok {
+			if toName, matched := toFieldNames.match(field); matched && hasMember(toType, toName) {
+				mapping.AmbiguousSource = append(mapping.AmbiguousSource, name)
+			}
+			continue
+		}
+
+		toNames := overrides.renamedTo(name)
+		toName, matched := toFieldNames.match(field)
+		_, redirected := fromPaths[toName]
+		if path, ok := toPaths[name]; ok {
+			toNames = append(toNames, path)
+		} else if matched && !redirected && !overrides.ignored(toName) && !overrides.overridden(toName) {
+			toNames = append(toNames, toName)
+		} else if len(toNames) == 0 && (!matched || overrides.ignored(toName)) {
+			continue
+		}
+
+		var found, mapped bool
+		for _, toName := range toNames {
+			toField, ok := FieldByPath(toType, toName)
+			if !ok {
+				continue
+			}
+			found = true
+
+			conversion := fieldConversion(toField.Type, field.Type)
+			if overrides != nil && overrides.Convert[toName] {
+				conversion = ConversionFunc
+			}
+			mapping.Fields = append(mapping.Fields, FieldMapping{FromField: name, ToField: toName, Conversion: conversion})
+			if conversion != ConversionNone {
+				mappedDest[strings.Split(toName, ".")[0]] = true
+				mapped = true
+			}
+		}
+		if mapped {
+			if field.Anonymous {
+				addFieldNames(copiedWhole, field.Type)
+			}
+			continue
+		}
+
+		if method, ok := setter(toType, name, opts.SetterPrefix, field.Type); !found && ok {
+			mapping.Fields = append(mapping.Fields, FieldMapping{FromField: name, ToMethod: method.Name, Conversion: ConversionAssign})
+			continue
+		}
+		if usedSource[name] || copiedWhole[name] {
+			// read through a tagged path, or copied with the embedded struct
+			continue
+		}
+		mapping.UnknownSource = append(mapping.UnknownSource, name)
+	}
+
+	// Copy from method to field
+	var funcs []FieldMapping
+	seen = map[string]bool{}
+	copiedWhole = map[string]bool{}
+	for _, field := range resolveFields(toType, fromType) {
+		name := field.Name
+		if !field.Exported || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if toFieldNames.excluded[name] || overrides.ignored(name) {
+			mapping.ExcludedDest = append(mapping.ExcludedDest, name)
+			continue
+		}
+
+		toField, ok := FieldByName(toType, name)
+		if !ok {
+			if hasMember(fromType, name) {
+				mapping.AmbiguousDest = append(mapping.AmbiguousDest, name)
+			}
+			continue
+		}
+
+		if overrides != nil && overrides.Func[name] {
+			funcs = append(funcs, FieldMapping{ToField: name, Conversion: ConversionFunc})
+			mappedDest[name] = true
+		} else if overrides.renamed(name) || overrides.skipped(name) {
+			// the renamed source field is copied, skipped fields stay missing
+		} else if path, ok := fromPaths[name]; ok {
+			fromField, _ := FieldByPath(fromType, path)
+			conversion := fieldConversion(toField.Type, fromField.Type)
+			mapping.Fields = append(mapping.Fields, FieldMapping{FromField: path, ToField: name, Conversion: conversion})
+			if conversion != ConversionNone {
+				mappedDest[name] = true
+			}
+		} else if method, ok := getter(fromType, name, opts.GetterPrefix); ok && (method.Name == name || !mappedDest[name]) {
+			// prefixed getters are only used for fields that aren't copied from a source field
+			conversion := setConversion(toField.Type, method.Out[0])
+			mapping.Fields = append(mapping.Fields, FieldMapping{FromMethod: method.Name, ToField: name, Conversion: conversion})
+			if conversion != ConversionNone {
+				mappedDest[name] = true
+			}
+		}
+
+		if field.Anonymous && mappedDest[name] {
+			addFieldNames(copiedWhole, field.Type)
+		}
+		if mappedDest[name] {
+			mapping.ToFields = append(mapping.ToFields, name)
+		} else if !copiedWhole[name] {
+			mapping.ToFields = append(mapping.ToFields, name)
+			mapping.MissingDest = append(mapping.MissingDest, name)
+		}
+	}
+
+	// Copy from method to method, for names that neither type has a field for
+	usedSetters := map[string]bool{}
+	for _, field := range mapping.Fields {
+		usedSetters[field.ToMethod] = true
+	}
+	for _, method := range fromType.PtrMethods() {
+		for _, name := range getterFieldNames(method.Name, opts.GetterPrefix) {
+			if fromGetter, ok := getter(fromType, name, opts.GetterPrefix); !ok || fromGetter.Name != method.Name {
+				continue
+			}
+			if _, ok := FieldByName(fromType, name); ok {
+				continue
+			}
+			if _, ok := FieldByName(toType, name); ok {
+				continue
+			}
+			if toSetter, ok := setter(toType, name, opts.SetterPrefix, method.Out[0]); ok && !usedSetters[toSetter.Name] {
+				usedSetters[toSetter.Name] = true
+				mapping.Fields = append(mapping.Fields, FieldMapping{FromMethod: method.Name, ToMethod: toSetter.Name, Conversion: ConversionAssign})
+				break
+			}
+		}
+	}
+
+	// funcs are called last to override the fields they set
+	mapping.Fields = append(mapping.Fields, funcs...)
+
+	mapping.suggest()
+	return mapping
+}
+
+// getter returns the getter of the struct reflectType for the field name, a
+// method without arguments returning a value, and optionally an error, named
+// like the field or with prefix
+func getter(reflectType Type, name, prefix string) (Method, bool) {
+	for _, methodName := range accessorNames(name, prefix) {
+		method, ok := ptrMethod(reflectType, methodName)
+		if ok && len(method.In) == 0 && (len(method.Out) == 1 || (len(method.Out) == 2 && isErrorType(method.Out[1]))) {
+			return method, true
+		}
+	}
+	return Method{}, false
+}
+
+// setter returns the setter of the struct reflectType for the field name, a
+// method taking a value of fieldType named like the field or with prefix. A
+// nil fieldType matches setters of any type.
+func setter(reflectType Type, name, prefix string, fieldType Type) (Method, bool) {
+	for _, methodName := range accessorNames(name, prefix) {
+		if method, ok := ptrMethod(reflectType, methodName); ok && len(method.In) == 1 && (fieldType == nil || fieldType.AssignableTo(method.In[0])) {
+			return method, true
+		}
+	}
+	return Method{}, false
+}
+
+// accessorNames returns the names of the accessors of the field name, in order of precedence
+func accessorNames(name, prefix string) []string {
+	if prefix == "" {
+		return []string{name}
+	}
+	return []string{name, prefix + name}
+}
+
+// getterFieldNames returns the names of the fields the method name can be
+// the getter of, in order of precedence
+func getterFieldNames(name, prefix string) []string {
+	if prefix != "" && len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+		return []string{name, name[len(prefix):]}
+	}
+	return []string{name}
+}
+
+// fieldConversion returns how a field of fromType is copied to a field of toType
+func fieldConversion(toType, fromType Type) Conversion {
+	if fromType.Nullable() {
+		if fromType.AssignableTo(toType) {
+			return ConversionAssign
+		}
+		return ConversionValuer
+	}
+
+	if conversion := setConversion(toType, fromType); conversion != ConversionNone {
+		return conversion
+	}
+
+	// copy recursively, values in interfaces are only known when copying
+	toElem, fromElem := Indirect(toType), Indirect(fromType)
+	if (toElem.Kind() == Struct && fromElem.Kind() == Struct) || toElem.Kind() == Interface || fromElem.Kind() == Interface {
+		return ConversionRecursive
+	}
+	return ConversionNone
+}
+
+// setConversion returns how a value of fromType is set to toType
+func setConversion(toType, fromType Type) Conversion {
+	if fromType.AssignableTo(toType) {
+		return ConversionAssign
+	}
+
+	fromType, toType = indirectPtr(fromType), indirectPtr(toType)
+	if fromType.ConvertibleTo(toType) {
+		return ConversionConvert
+	}
+	if toType.PtrScanner() {
+		return ConversionScanner
+	}
+	return ConversionNone
+}
+
+// deepFields returns the fields of reflectType, flattening anonymous structs.
+// Names can be repeated, FieldByName selects them following Go's rules.
+func deepFields(reflectType Type) []Field {
+	return appendDeepFields(nil, Indirect(reflectType), map[Type]bool{})
+}
+
+func appendDeepFields(fields []Field, structType Type, visited map[Type]bool) []Field {
+	if structType.Kind() != Struct || visited[structType] {
+		return fields
+	}
+	visited[structType] = true
+
+	for _, field := range structType.Fields() {
+		if fieldType := indirectPtr(field.Type); field.Anonymous && fieldType.Kind() == Struct {
+			fields = appendDeepFields(fields, fieldType, visited)
+		} else {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// resolveFields returns the fields of structType like deepFields, adding the
+// embedded structs that otherType has a regular field of the same name for
+// before their promoted fields, so they can be copied whole
+func resolveFields(structType, otherType Type) []Field {
+	var fields []Field
+	for _, field := range appendEmbeddedFields(nil, Indirect(structType), map[Type]bool{}) {
+		if !field.Anonymous {
+			fields = append(fields, field)
+			continue
+		}
+		embedded, ok := FieldByName(structType, field.Name)
+		if !ok || !embedded.Anonymous || !embedded.Exported {
+			continue
+		}
+		if other, ok := FieldByName(otherType, field.Name); ok && other.Exported && !other.Anonymous {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// appendEmbeddedFields is appendDeepFields keeping the embedded structs
+// before their fields
+func appendEmbeddedFields(fields []Field, structType Type, visited map[Type]bool) []Field {
+	if structType.Kind() != Struct || visited[structType] {
+		return fields
+	}
+	visited[structType] = true
+
+	for _, field := range structType.Fields() {
+		fields = append(fields, field)
+		if fieldType := indirectPtr(field.Type); field.Anonymous && fieldType.Kind() == Struct {
+			fields = appendEmbeddedFields(fields, fieldType, visited)
+		}
+	}
+	return fields
+}
+
+// addFieldNames adds the names of the fields of the struct reflectType,
+// including promoted ones, to names
+func addFieldNames(names map[string]bool, reflectType Type) {
+	for _, field := range deepFields(reflectType) {
+		names[field.Name] = true
+	}
+}
+
+// hasMember reports whether the struct reflectType has an exported field,
+// possibly ambiguous, or a pointer method named name
+func hasMember(reflectType Type, name string) bool {
+	if _, ok := ptrMethod(reflectType, name); ok {
+		return true
+	}
+	for _, field := range deepFields(reflectType) {
+		if field.Name == name && field.Exported {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldByName returns the field of the struct reflectType by name following
+// Go's rules for promoted fields, ambiguous names aren't found
+func FieldByName(reflectType Type, name string) (Field, bool) {
+	var (
+		current = []Type{reflectType}
+		counts  = map[Type]int{reflectType: 1}
+		visited = map[Type]bool{}
+	)
+
+	for len(current) > 0 {
+		var (
+			next       []Type
+			nextCounts = map[Type]int{}
+			found      []Field
+		)
+
+		// a type embedded through several paths at the same depth has its
+		// fields found once per path, types seen at a lower depth are shadowed
+		for _, structType := range current {
+			if visited[structType] {
+				continue
+			}
+			visited[structType] = true
+
+			for _, field := range structType.Fields() {
+				if field.Name == name {
+					for i := 0; i < counts[structType]; i++ {
+						found = append(found, field)
+					}
+				}
+				if fieldType := indirectPtr(field.Type); field.Anonymous && fieldType.Kind() == Struct {
+					if nextCounts[fieldType] == 0 {
+						next = append(next, fieldType)
+					}
+					nextCounts[fieldType] += counts[structType]
+				}
+			}
+		}
+
+		if len(found) == 1 {
+			return found[0], true
+		} else if len(found) > 1 {
+			return Field{}, false
+		}
+		current, counts = next, nextCounts
+	}
+
+	return Field{}, false
+}
+
+// FieldByPath returns the field of the struct reflectType at the dotted
+// path, following pointers to structs
+func FieldByPath(reflectType Type, path string) (Field, bool) {
+	var field Field
+	for i, name := range strings.Split(path, ".") {
+		if i > 0 {
+			reflectType = indirectPtr(field.Type)
+		}
+		if reflectType.Kind() != Struct {
+			return Field{}, false
+		}
+
+		var ok bool
+		if field, ok = FieldByName(reflectType, name); !ok || !field.Exported {
+			return Field{}, false
+		}
+	}
+	return field, true
+}
+
+// PathTag is the struct tag key giving the path of the field in the other
+// type, e.g. `copier:"Address.City"`
+const PathTag = "copier"
+
+// tagPaths returns the paths of otherType that the fields of taggedType are
+// tagged with, by field name
+func tagPaths(taggedType, otherType Type) map[string]string {
+	paths := map[string]string{}
+	for _, field := range deepFields(taggedType) {
+		path := field.Tag.Get(PathTag)
+		if path == "" || !field.Exported {
+			continue
+		}
+		if _, ok := FieldByName(taggedType, field.Name); !ok {
+			continue
+		}
+		if _, ok := FieldByPath(otherType, path); ok {
+			paths[field.Name] = path
+		}
+	}
+	return paths
+}
+
+// flattenPaths adds the paths of otherType whose names concatenate to the
+// names of the fields of flatType without a field or method of the same
+// name, e.g. Customer.Name for CustomerName, unless skip returns true
+func flattenPaths(paths map[string]string, flatType, otherType Type, skip func(name string) bool) {
+	for _, field := range deepFields(flatType) {
+		name := field.Name
+		if _, tagged := paths[name]; tagged || !field.Exported || skip(name) {
+			continue
+		}
+		if _, ok := FieldByName(flatType, name); !ok {
+			continue
+		}
+		if _, ok := FieldByName(otherType, name); ok {
+			continue
+		}
+		if _, ok := ptrMethod(otherType, name); ok {
+			continue
+		}
+
+		if path, ok := flattenPath(otherType, name); ok {
+			paths[name] = path
+		}
+	}
+}
+
+// flattenPath returns the path of the nested field of structType whose names
+// concatenate to name, trying fields in order
+func flattenPath(structType Type, name string) (string, bool) {
+	for _, field := range deepFields(structType) {
+		fieldType := indirectPtr(field.Type)
+		if !field.Exported || len(field.Name) >= len(name) || !strings.HasPrefix(name, field.Name) || fieldType.Kind() != Struct {
+			continue
+		}
+		if _, ok := FieldByName(structType, field.Name); !ok {
+			continue
+		}
+
+		rest := name[len(field.Name):]
+		if nested, ok := FieldByName(fieldType, rest); ok && nested.Exported {
+			return field.Name + "." + rest, true
+		}
+		if path, ok := flattenPath(fieldType, rest); ok {
+			return field.Name + "." + path, true
+		}
+	}
+	return "", false
+}
+
+// tagName returns the name given to field by the struct tag key, and whether
+// the field is excluded with "-". An empty name means the tag has no name.
+func tagName(field Field, key string) (name string, excluded bool) {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return "", false
+	}
+	if tag == "-" {
+		return "", true
+	}
+	if idx := strings.Index(tag, ","); idx != -1 {
+		tag = tag[:idx]
+	}
+	return tag, false
+}
+
+// tagFieldNames indexes the fields of a struct by their tag names
+type tagFieldNames struct {
+	key      string
+	names    map[string]string
+	excluded map[string]bool
+}
+
+// fieldNamesByTag maps the tag names of the fields of reflectType to their Go
+// names, fields without a tag name are keyed by their Go name.
+func fieldNamesByTag(reflectType Type, key string) tagFieldNames {
+	fieldNames := tagFieldNames{key: key, names: map[string]string{}, excluded: map[string]bool{}}
+	if key == "" {
+		return fieldNames
+	}
+
+	for _, field := range deepFields(reflectType) {
+		name, excluded := tagName(field, key)
+		if excluded {
+			fieldNames.excluded[field.Name] = true
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := fieldNames.names[name]; !ok {
+			fieldNames.names[name] = field.Name
+		}
+	}
+	return fieldNames
+}
+
+// FieldNameByTag returns the Go name of the exported field of reflectType
+// that a source named name is copied to, matching the tag names of key first
+// and then the Go names like Resolve
+func FieldNameByTag(reflectType Type, key, name string) (string, bool) {
+	fieldNames := fieldNamesByTag(reflectType, key)
+	if goName, ok := fieldNames.names[name]; ok {
+		name = goName
+	} else if fieldNames.excluded[name] {
+		return "", false
+	}
+
+	field, ok := FieldByName(reflectType, name)
+	return name, ok && field.Exported
+}
+
+// match returns the Go name of the field that field should be copied to,
+// matching by tag name first and then by Go name.
+func (fieldNames tagFieldNames) match(field Field) (string, bool) {
+	if fieldNames.key == "" {
+		return field.Name, true
+	}
+
+	name, excluded := tagName(field, fieldNames.key)
+	if excluded {
+		return "", false
+	}
+	if toName, ok := fieldNames.names[name]; ok && name != "" {
+		return toName, true
+	}
+	if fieldNames.excluded[field.Name] {
+		return "", false
+	}
+	return field.Name, true
+}
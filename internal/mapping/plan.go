@@ -0,0 +1,217 @@
+package mapping
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Conversion is how a value is copied from its source to its destination
+type Conversion string
+
+// Conversions used by Copy
+const (
+	ConversionNone      Conversion = "none"      // the value can't be copied
+	ConversionAssign    Conversion = "assign"    // the value is assigned
+	ConversionConvert   Conversion = "convert"   // the value is converted to the destination type
+	ConversionScanner   Conversion = "scanner"   // the destination scans the value with sql.Scanner
+	ConversionValuer    Conversion = "valuer"    // the value is read with driver.Valuer
+	ConversionRecursive Conversion = "recursive" // the value is copied with Copy
+	ConversionFunc      Conversion = "func"      // the value is returned by a func
+)
+
+// MemberKind is the kind of struct member a value is copied from or to
+type MemberKind string
+
+// Member kinds
+const (
+	MemberNone   MemberKind = "none"
+	MemberField  MemberKind = "field"
+	MemberMethod MemberKind = "method"
+	MemberFunc   MemberKind = "func"
+)
+
+// Plan describes what Copy does when copying between two struct types
+type Plan struct {
+	From          string      `json:"from"`
+	To            string      `json:"to"`
+	Fields        []FieldPlan `json:"fields"`
+	UnusedSource  []string    `json:"unused_source,omitempty"`  // source fields that aren't copied
+	UnusedSetters []string    `json:"unused_setters,omitempty"` // destination setters that aren't called
+
+	// fields that can't be copied because Go can't select them
+	AmbiguousSource []string `json:"ambiguous_source,omitempty"`
+	AmbiguousDest   []string `json:"ambiguous_dest,omitempty"`
+}
+
+// FieldPlan describes how a destination field or setter gets its value
+type FieldPlan struct {
+	Dest       string     `json:"dest"`
+	DestKind   MemberKind `json:"dest_kind"`
+	Source     string     `json:"source,omitempty"`
+	SourceKind MemberKind `json:"source_kind"`
+	Conversion Conversion `json:"conversion"`
+	Warnings   []string   `json:"warnings,omitempty"`
+}
+
+// Plan describes mapping, sharing no slices with it
+func (mapping *Mapping) Plan() *Plan {
+	plan := &Plan{
+		From:            mapping.FromType.String(),
+		To:              mapping.ToType.String(),
+		UnusedSource:    append([]string(nil), mapping.UnknownSource...),
+		AmbiguousSource: append([]string(nil), mapping.AmbiguousSource...),
+		AmbiguousDest:   append([]string(nil), mapping.AmbiguousDest...),
+	}
+
+	// the last copy that can set a destination field wins
+	sources := map[string][]FieldMapping{}
+	usedSetters := map[string]bool{}
+	for _, field := range mapping.Fields {
+		if field.ToMethod != "" {
+			usedSetters[field.ToMethod] = true
+			continue
+		}
+		sources[field.ToField] = append(sources[field.ToField], field)
+	}
+
+	for _, name := range mapping.ToFields {
+		fieldPlan := FieldPlan{Dest: name, DestKind: MemberField, SourceKind: MemberNone, Conversion: ConversionNone}
+
+		var copied []FieldMapping
+		for _, field := range sources[name] {
+			if field.Conversion != ConversionNone {
+				copied = append(copied, field)
+			} else {
+				fieldPlan.Warnings = append(fieldPlan.Warnings, mapping.incompatible(field))
+			}
+		}
+
+		if len(copied) > 0 {
+			field := copied[len(copied)-1]
+			fieldPlan.Conversion = field.Conversion
+			if field.FromMethod != "" {
+				fieldPlan.Source, fieldPlan.SourceKind = field.FromMethod, MemberMethod
+			} else if field.FromField == "" {
+				fieldPlan.SourceKind = MemberFunc
+			} else {
+				fieldPlan.Source, fieldPlan.SourceKind = field.FromField, MemberField
+			}
+
+			for _, overwritten := range copied[:len(copied)-1] {
+				fieldPlan.Warnings = append(fieldPlan.Warnings, fmt.Sprintf("%v is overwritten by %v", overwritten.Source(), field.Source()))
+			}
+		} else if suggestions := mapping.Suggestions[name]; len(suggestions) > 0 {
+			fieldPlan.Warnings = append(fieldPlan.Warnings, "did you mean "+strings.Join(suggestions, " or ")+"?")
+		}
+
+		plan.Fields = append(plan.Fields, fieldPlan)
+	}
+
+	for _, field := range mapping.Fields {
+		if field.ToMethod != "" {
+			fieldPlan := FieldPlan{
+				Dest:       field.ToMethod,
+				DestKind:   MemberMethod,
+				Source:     field.FromField,
+				SourceKind: MemberField,
+				Conversion: field.Conversion,
+			}
+			if field.FromMethod != "" {
+				fieldPlan.Source, fieldPlan.SourceKind = field.FromMethod, MemberMethod
+			}
+			plan.Fields = append(plan.Fields, fieldPlan)
+		} else if strings.Contains(field.ToField, ".") {
+			fieldPlan := FieldPlan{
+				Dest:       field.ToField,
+				DestKind:   MemberField,
+				Source:     field.FromField,
+				SourceKind: MemberField,
+				Conversion: field.Conversion,
+			}
+			if field.Conversion == ConversionNone {
+				fieldPlan.Warnings = append(fieldPlan.Warnings, mapping.incompatible(field))
+			}
+			plan.Fields = append(plan.Fields, fieldPlan)
+		}
+	}
+
+	for _, method := range mapping.ToType.PtrMethods() {
+		isSetter := len(method.Out) == 0 || (len(method.Out) == 1 && isErrorType(method.Out[0]))
+		if len(method.In) == 1 && isSetter && !usedSetters[method.Name] {
+			plan.UnusedSetters = append(plan.UnusedSetters, method.Name)
+		}
+	}
+
+	return plan
+}
+
+// incompatible returns a warning for a field that can't be copied
+func (mapping *Mapping) incompatible(field FieldMapping) string {
+	var fromType Type
+	if method, ok := ptrMethod(mapping.FromType, field.FromMethod); ok {
+		fromType = method.Out[0]
+	} else if structField, ok := FieldByPath(mapping.FromType, field.FromField); ok {
+		fromType = structField.Type
+	}
+	toField, _ := FieldByPath(mapping.ToType, field.ToField)
+	return fmt.Sprintf("can't copy %v from %v to %v", field.Source(), fromType, toField.Type)
+}
+
+// String formats plan as a table
+func (plan *Plan) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v -> %v\n", plan.From, plan.To)
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DEST\tSOURCE\tCONVERSION\tWARNINGS")
+	for _, field := range plan.Fields {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", field.DestName(), field.SourceName(), field.Conversion, strings.Join(field.Warnings, "; "))
+	}
+	w.Flush()
+
+	// tabwriter pads the empty warnings column
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	buf.Reset()
+	buf.WriteString(strings.Join(lines, "\n"))
+
+	if len(plan.UnusedSource) > 0 {
+		fmt.Fprintf(&buf, "unused source fields: %v\n", strings.Join(plan.UnusedSource, ", "))
+	}
+	if len(plan.UnusedSetters) > 0 {
+		fmt.Fprintf(&buf, "unused setters: %v\n", strings.Join(plan.UnusedSetters, ", "))
+	}
+	if len(plan.AmbiguousSource) > 0 {
+		fmt.Fprintf(&buf, "ambiguous source fields: %v\n", strings.Join(plan.AmbiguousSource, ", "))
+	}
+	if len(plan.AmbiguousDest) > 0 {
+		fmt.Fprintf(&buf, "ambiguous destination fields: %v\n", strings.Join(plan.AmbiguousDest, ", "))
+	}
+	return buf.String()
+}
+
+// DestName returns the destination name, methods are suffixed with ()
+func (field FieldPlan) DestName() string {
+	return memberName(field.Dest, field.DestKind)
+}
+
+// SourceName returns the source name, methods are suffixed with ()
+func (field FieldPlan) SourceName() string {
+	return memberName(field.Source, field.SourceKind)
+}
+
+func memberName(name string, kind MemberKind) string {
+	switch kind {
+	case MemberMethod:
+		return name + "()"
+	case MemberNone:
+		return "-"
+	case MemberFunc:
+		return "func"
+	}
+	return name
+}
@@ -0,0 +1,125 @@
+package mapping
+
+import (
+	"sort"
+	"strings"
+)
+
+// suggest finds the closest unused names on the other type for the unmapped fields
+func (mapping *Mapping) suggest() {
+	mapping.Suggestions = map[string][]string{}
+	if len(mapping.UnknownSource) == 0 && len(mapping.MissingDest) == 0 {
+		return
+	}
+
+	usedMethods := map[string]bool{}
+	for _, field := range mapping.Fields {
+		usedMethods[field.FromMethod] = true
+		usedMethods[field.ToMethod] = true
+	}
+
+	toCandidates := append([]string{}, mapping.MissingDest...)
+	for _, method := range mapping.ToType.PtrMethods() {
+		if len(method.In) == 1 && !usedMethods[method.Name] {
+			toCandidates = append(toCandidates, method.Name)
+		}
+	}
+	for _, name := range mapping.UnknownSource {
+		if names := SuggestNames(name, toCandidates); len(names) > 0 {
+			mapping.Suggestions[name] = names
+		}
+	}
+
+	fromCandidates := append([]string{}, mapping.UnknownSource...)
+	for _, method := range mapping.FromType.PtrMethods() {
+		if len(method.In) == 0 && len(method.Out) == 1 && !usedMethods[method.Name] {
+			fromCandidates = append(fromCandidates, method.Name)
+		}
+	}
+	for _, name := range mapping.MissingDest {
+		if names := SuggestNames(name, fromCandidates); len(names) > 0 {
+			mapping.Suggestions[name] = names
+		}
+	}
+}
+
+// DescribeNames joins names, adding the suggestions for each of them
+func DescribeNames(names []string, suggestions map[string][]string) string {
+	descriptions := make([]string, len(names))
+	for i, name := range names {
+		descriptions[i] = name
+		if names := suggestions[name]; len(names) > 0 {
+			descriptions[i] += " (did you mean " + strings.Join(names, " or ") + "?)"
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// maxSuggestions is the number of candidate names suggested for an unmatched field
+const maxSuggestions = 3
+
+// SuggestNames returns the candidates closest to name, ignoring case, nearest first
+func SuggestNames(name string, candidates []string) []string {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+
+	var (
+		suggestions []suggestion
+		folded      = strings.ToLower(name)
+		maxDistance = (len(name) + 2) / 3
+	)
+
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		if distance := editDistance(folded, strings.ToLower(candidate)); distance <= maxDistance && distance < len(name) {
+			suggestions = append(suggestions, suggestion{name: candidate, distance: distance})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+
+	var names []string
+	for i := 0; i < len(suggestions) && i < maxSuggestions; i++ {
+		names = append(names, suggestions[i].name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,20 @@
+package mapping
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestNames(t *testing.T) {
+	candidates := []string{"EmployeID", "Employee", "Name", "Nickname", "SuperRule"}
+
+	if names := SuggestNames("Employe", candidates); !reflect.DeepEqual(names, []string{"Employee", "EmployeID"}) {
+		t.Errorf("Unexpected suggestions %v", names)
+	}
+	if names := SuggestNames("nickName", candidates); !reflect.DeepEqual(names, []string{"Nickname"}) {
+		t.Errorf("Unexpected suggestions %v", names)
+	}
+	if names := SuggestNames("Birthday", candidates); names != nil {
+		t.Errorf("Should not suggest unrelated names, but got %v", names)
+	}
+}
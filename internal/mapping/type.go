@@ -0,0 +1,174 @@
+// Package mapping resolves how copier copies between two struct types. It
+// works on reflect types for Copy and on go/types types, implemented by the
+// gotypes package, for static tools, so both follow the same rules.
+package mapping
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+)
+
+// Kind is the kind of a Type
+type Kind int
+
+// Kinds of types the mapping distinguishes
+const (
+	Other Kind = iota
+	Struct
+	Ptr
+	Slice
+	Interface
+)
+
+// Type is a type copied from or to, all types compared with each other
+// must come from the same implementation
+type Type interface {
+	String() string
+	Kind() Kind
+
+	// Elem returns the element type of a Ptr or Slice
+	Elem() Type
+
+	// Fields returns the direct fields of a Struct
+	Fields() []Field
+
+	// PtrMethods returns the exported methods of a pointer to the type
+	PtrMethods() []Method
+
+	AssignableTo(Type) bool
+	ConvertibleTo(Type) bool
+
+	// Nullable reports whether the type implements driver.Valuer
+	Nullable() bool
+
+	// PtrScanner reports whether a pointer to the type implements sql.Scanner
+	PtrScanner() bool
+}
+
+// Field is a struct field
+type Field struct {
+	Name      string
+	Exported  bool
+	Anonymous bool
+	Tag       reflect.StructTag
+	Type      Type
+}
+
+// Method is a method without its receiver
+type Method struct {
+	Name string
+	In   []Type
+	Out  []Type
+}
+
+// ptrMethod returns the method of a pointer to reflectType by name
+func ptrMethod(reflectType Type, name string) (Method, bool) {
+	for _, method := range reflectType.PtrMethods() {
+		if method.Name == name {
+			return method, true
+		}
+	}
+	return Method{}, false
+}
+
+// isErrorType reports whether reflectType is the error interface
+func isErrorType(reflectType Type) bool {
+	return reflectType.Kind() == Interface && reflectType.String() == "error"
+}
+
+// Indirect returns the struct or element type behind pointers and slices
+func Indirect(reflectType Type) Type {
+	for reflectType.Kind() == Ptr || reflectType.Kind() == Slice {
+		reflectType = reflectType.Elem()
+	}
+	return reflectType
+}
+
+func indirectPtr(reflectType Type) Type {
+	for reflectType.Kind() == Ptr {
+		reflectType = reflectType.Elem()
+	}
+	return reflectType
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// reflectType is a Type implemented by reflect
+type reflectType struct {
+	reflect.Type
+}
+
+// Reflect returns the Type of a reflect.Type
+func Reflect(t reflect.Type) Type {
+	return reflectType{t}
+}
+
+func (t reflectType) Kind() Kind {
+	switch t.Type.Kind() {
+	case reflect.Struct:
+		return Struct
+	case reflect.Ptr:
+		return Ptr
+	case reflect.Slice:
+		return Slice
+	case reflect.Interface:
+		return Interface
+	}
+	return Other
+}
+
+func (t reflectType) Elem() Type {
+	return reflectType{t.Type.Elem()}
+}
+
+func (t reflectType) Fields() []Field {
+	fields := make([]Field, t.NumField())
+	for i := range fields {
+		field := t.Field(i)
+		fields[i] = Field{
+			Name:      field.Name,
+			Exported:  field.PkgPath == "",
+			Anonymous: field.Anonymous,
+			Tag:       field.Tag,
+			Type:      reflectType{field.Type},
+		}
+	}
+	return fields
+}
+
+func (t reflectType) PtrMethods() []Method {
+	ptrType := reflect.PtrTo(t.Type)
+	methods := make([]Method, ptrType.NumMethod())
+	for i := range methods {
+		method := ptrType.Method(i)
+		methods[i].Name = method.Name
+		// skip the receiver
+		for j := 1; j < method.Type.NumIn(); j++ {
+			methods[i].In = append(methods[i].In, reflectType{method.Type.In(j)})
+		}
+		for j := 0; j < method.Type.NumOut(); j++ {
+			methods[i].Out = append(methods[i].Out, reflectType{method.Type.Out(j)})
+		}
+	}
+	return methods
+}
+
+func (t reflectType) AssignableTo(u Type) bool {
+	return t.Type.AssignableTo(u.(reflectType).Type)
+}
+
+func (t reflectType) ConvertibleTo(u Type) bool {
+	return t.Type.ConvertibleTo(u.(reflectType).Type)
+}
+
+func (t reflectType) Nullable() bool {
+	return t.Type.ConvertibleTo(valuerType)
+}
+
+func (t reflectType) PtrScanner() bool {
+	return reflect.PtrTo(t.Type).Implements(scannerType)
+}
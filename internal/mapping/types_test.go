@@ -0,0 +1,157 @@
+package mapping
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type User struct {
+	Name     string
+	Birthday *time.Time
+	Nickname string `json:"nick"`
+	Role     string
+	Age      int32
+	Income   sql.NullFloat64
+	Address  Address
+	Notes    []string
+	Emial    string
+	Base     Base
+	flags    []byte
+}
+
+func (user User) DoubleAge() int32 {
+	return 2 * user.Age
+}
+
+func (user *User) GetEmail() string {
+	return user.Emial
+}
+
+func (user *User) Balance() (sql.NullInt64, error) {
+	return sql.NullInt64{}, nil
+}
+
+func (user User) Title() string {
+	return "Dr"
+}
+
+type Address struct {
+	City string
+}
+
+type Base struct {
+	ID int64
+}
+
+type Employee struct {
+	Base
+	Name      *string
+	Birthday  time.Time
+	Nick      string `json:"nick,omitempty"`
+	Age       int64
+	Income    *float64
+	Address   *Address
+	Notes     []string
+	DoubleAge int32
+	Email     string
+	Balance   sql.NullInt64
+	SuperRule string
+	City      string `copier:"Address.City"`
+}
+
+func (employee *Employee) Role(role string) {
+	employee.SuperRule = "Super " + role
+}
+
+func (employee *Employee) Reset(all bool) {}
+
+func (employee *Employee) SetTitle(title string) {}
+
+func TestResolveTagPaths(t *testing.T) {
+	flatten := Resolve(Reflect(reflect.TypeOf(Employee{})), Reflect(reflect.TypeOf(User{})), Options{})
+	if !containsField(flatten.Fields, FieldMapping{FromField: "Address.City", ToField: "City", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy City from Address.City, but got %+v", flatten.Fields)
+	}
+
+	unflatten := Resolve(Reflect(reflect.TypeOf(User{})), Reflect(reflect.TypeOf(Employee{})), Options{})
+	if !containsField(unflatten.Fields, FieldMapping{FromField: "City", ToField: "Address.City", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy City to Address.City, but got %+v", unflatten.Fields)
+	}
+	for _, name := range unflatten.UnknownSource {
+		if name == "City" {
+			t.Errorf("City should not be an unknown source field")
+		}
+	}
+
+	plan := unflatten.Plan()
+	if last := plan.Fields[len(plan.Fields)-1]; last.Dest != "Address.City" || last.Source != "City" {
+		t.Errorf("Should explain the path, but got %+v", last)
+	}
+}
+
+func TestResolveEmbeddedStructs(t *testing.T) {
+	embed := Resolve(Reflect(reflect.TypeOf(Employee{})), Reflect(reflect.TypeOf(User{})), Options{})
+	if !containsField(embed.Fields, FieldMapping{FromField: "Base", ToField: "Base", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy Base to the embedded Base, but got %+v", embed.Fields)
+	}
+	for _, name := range embed.MissingDest {
+		if name == "ID" {
+			t.Errorf("ID should not be a missing destination field")
+		}
+	}
+
+	field := Resolve(Reflect(reflect.TypeOf(User{})), Reflect(reflect.TypeOf(Employee{})), Options{})
+	if !containsField(field.Fields, FieldMapping{FromField: "Base", ToField: "Base", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy the embedded Base to Base, but got %+v", field.Fields)
+	}
+	for _, name := range field.UnknownSource {
+		if name == "ID" {
+			t.Errorf("ID should not be an unknown source field")
+		}
+	}
+}
+
+func TestResolveAccessorPrefixes(t *testing.T) {
+	toType, fromType := Reflect(reflect.TypeOf(Employee{})), Reflect(reflect.TypeOf(User{}))
+
+	if typeMapping := Resolve(toType, fromType, Options{}); containsField(typeMapping.Fields, FieldMapping{FromMethod: "GetEmail", ToField: "Email", Conversion: ConversionAssign}) {
+		t.Errorf("Should not copy from prefixed getters without a prefix, but got %+v", typeMapping.Fields)
+	}
+
+	typeMapping := Resolve(toType, fromType, Options{GetterPrefix: "Get"})
+	if !containsField(typeMapping.Fields, FieldMapping{FromMethod: "GetEmail", ToField: "Email", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy Email from GetEmail, but got %+v", typeMapping.Fields)
+	}
+}
+
+func TestResolveErrorGetters(t *testing.T) {
+	typeMapping := Resolve(Reflect(reflect.TypeOf(Employee{})), Reflect(reflect.TypeOf(User{})), Options{})
+	if !containsField(typeMapping.Fields, FieldMapping{FromMethod: "Balance", ToField: "Balance", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy Balance from a getter returning an error, but got %+v", typeMapping.Fields)
+	}
+}
+
+func TestResolveMethodToMethod(t *testing.T) {
+	toType, fromType := Reflect(reflect.TypeOf(Employee{})), Reflect(reflect.TypeOf(User{}))
+
+	typeMapping := Resolve(toType, fromType, Options{SetterPrefix: "Set"})
+	if !containsField(typeMapping.Fields, FieldMapping{FromMethod: "Title", ToMethod: "SetTitle", Conversion: ConversionAssign}) {
+		t.Errorf("Should copy from Title to SetTitle, but got %+v", typeMapping.Fields)
+	}
+
+	plan := typeMapping.Plan()
+	if last := plan.Fields[len(plan.Fields)-1]; last.Dest != "SetTitle" || last.SourceKind != MemberMethod || last.Source != "Title" {
+		t.Errorf("Should explain method to method copies, but got %+v", last)
+	}
+}
+
+func containsField(fields []FieldMapping, field FieldMapping) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
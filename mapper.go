@@ -0,0 +1,362 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+// Mapper copies From values to new To values with per field settings. The
+// settings are checked when they're made, the first error is returned by Err
+// and Map. A configured Mapper can be used by multiple goroutines, but it
+// mustn't be configured while it's used.
+type Mapper[From any, To any] struct {
+	opt       Option
+	overrides mapping.Overrides
+	config    mapperConfig
+	inverses  map[string]reflect.Value // inverse Convert funcs by destination field
+	err       error
+}
+
+// mapperConfig is what a Mapper changes when copying between its struct types
+type mapperConfig struct {
+	toType, fromType reflect.Type
+	mapping          *mapping.Mapping
+	funcs            map[string]reflect.Value // by destination field
+}
+
+// NewMapper returns a Mapper from From to To, which must be structs or pointers to structs
+func NewMapper[From any, To any]() *Mapper[From, To] {
+	return NewMapperWithOption[From, To](Option{})
+}
+
+// NewMapperWithOption returns a Mapper from From to To copying with opt
+func NewMapperWithOption[From any, To any](opt Option) *Mapper[From, To] {
+	m := &Mapper[From, To]{
+		opt: opt,
+		overrides: mapping.Overrides{
+			Ignore:  map[string]bool{},
+			Rename:  map[string]string{},
+			Func:    map[string]bool{},
+			Convert: map[string]bool{},
+			Skip:    map[string]bool{},
+		},
+		inverses: map[string]reflect.Value{},
+		config: mapperConfig{
+			toType:   indirectPtrType(reflect.TypeOf((*To)(nil)).Elem()),
+			fromType: indirectPtrType(reflect.TypeOf((*From)(nil)).Elem()),
+			funcs:    map[string]reflect.Value{},
+		},
+	}
+
+	if m.config.toType.Kind() != reflect.Struct || m.config.fromType.Kind() != reflect.Struct {
+		m.err = fmt.Errorf("mapper from %v to %v: both types must be structs", m.config.fromType, m.config.toType)
+		return m
+	}
+	m.resolve()
+	return m
+}
+
+// Field sets the destination field name from source, which is either the name
+// of a source field or a func(From) V or func(From) (V, error), From may be a
+// pointer. Funcs are called after the other fields are copied.
+func (m *Mapper[From, To]) Field(name string, source interface{}) *Mapper[From, To] {
+	toField, ok := m.destField(name)
+	if !ok {
+		return m
+	}
+
+	if fromName, ok := source.(string); ok {
+		if _, ok := mapping.FieldByName(mapping.Reflect(m.config.fromType), fromName); !ok || !isExported(fromName) {
+			return m.fail("unknown source field %v", describeName(fromName, exportedFields(m.config.fromType)))
+		}
+		m.reset(name)
+		m.overrides.Rename[name] = fromName
+		return m.resolve()
+	}
+
+	fn := reflect.ValueOf(source)
+	if !isFunc(fn, m.config.fromType) && !isFunc(fn, reflect.PtrTo(m.config.fromType)) {
+		return m.fail("field %v: %T isn't a source field name or a func(%v) returning a value and an optional error", name, source, m.config.fromType)
+	}
+	if !settable(toField.Type, fn.Type().Out(0)) {
+		return m.fail("field %v: can't set %v to %v", name, fn.Type().Out(0), toField.Type)
+	}
+	m.reset(name)
+	m.overrides.Func[name] = true
+	m.config.funcs[name] = fn
+	return m.resolve()
+}
+
+// Ignore leaves the destination fields names unset
+func (m *Mapper[From, To]) Ignore(names ...string) *Mapper[From, To] {
+	for _, name := range names {
+		if _, ok := m.destField(name); !ok {
+			return m
+		}
+		m.reset(name)
+		m.overrides.Ignore[name] = true
+	}
+	return m.resolve()
+}
+
+// Convert sets the destination field name with fn, a func(V) D or
+// func(V) (D, error) called with the value of its source field
+func (m *Mapper[From, To]) Convert(name string, fn interface{}) *Mapper[From, To] {
+	toField, ok := m.destField(name)
+	if !ok {
+		return m
+	}
+
+	var fromField reflect.StructField
+	for _, field := range m.config.mapping.Fields {
+		if field.ToField == name && field.FromField != "" {
+			fromField, _ = m.config.fromType.FieldByName(field.FromField)
+		}
+	}
+	if fromField.Type == nil {
+		return m.fail("field %v isn't copied from a source field", name)
+	}
+
+	fnValue := reflect.ValueOf(fn)
+	if !isFunc(fnValue, fromField.Type) {
+		return m.fail("field %v: %T isn't a func(%v) returning a value and an optional error", name, fn, fromField.Type)
+	}
+	if !settable(toField.Type, fnValue.Type().Out(0)) {
+		return m.fail("field %v: can't set %v to %v", name, fnValue.Type().Out(0), toField.Type)
+	}
+	m.reset(name)
+	m.overrides.Rename[name] = fromField.Name
+	m.overrides.Convert[name] = true
+	m.config.funcs[name] = fnValue
+	return m.resolve()
+}
+
+// ConvertWithInverse is Convert with the inverse of fn, which Reverse uses to
+// convert the destination field back to its source field
+func (m *Mapper[From, To]) ConvertWithInverse(name string, fn interface{}, inverse interface{}) *Mapper[From, To] {
+	if m.Convert(name, fn); m.err != nil {
+		return m
+	}
+
+	toField, _ := m.config.toType.FieldByName(name)
+	fromField, _ := m.config.fromType.FieldByName(m.overrides.Rename[name])
+	inverseValue := reflect.ValueOf(inverse)
+	if !isFunc(inverseValue, toField.Type) {
+		return m.fail("field %v: inverse %T isn't a func(%v) returning a value and an optional error", name, inverse, toField.Type)
+	}
+	if !settable(fromField.Type, inverseValue.Type().Out(0)) {
+		return m.fail("field %v: inverse can't set %v to %v", name, inverseValue.Type().Out(0), fromField.Type)
+	}
+	m.inverses[name] = inverseValue
+	return m
+}
+
+// Reverse returns a Mapper from To to From with the settings of m reversed.
+// Renamed fields are copied back to their source fields and ignored fields
+// are ignored on both sides. Fields set by a func, or converted without an
+// inverse, can't be copied back and are left unmapped. Getters and setters
+// swap roles: a field copied from a getter is copied back to the matching
+// setter, and a field copied to a setter is copied back from the matching
+// getter, with the Option's accessor prefixes.
+func (m *Mapper[From, To]) Reverse() *Mapper[To, From] {
+	reverse := NewMapperWithOption[To, From](m.opt)
+	if m.err != nil {
+		reverse.err = m.err
+		return reverse
+	}
+
+	fromType := mapping.Reflect(m.config.fromType)
+	hasField := func(name string) bool {
+		_, ok := mapping.FieldByName(fromType, name)
+		return ok
+	}
+
+	for _, name := range sortedKeys(m.overrides.Rename) {
+		if !m.overrides.Convert[name] {
+			reverse.Field(m.overrides.Rename[name], name)
+		}
+	}
+	for _, name := range sortedKeys(m.overrides.Ignore) {
+		if hasField(name) {
+			reverse.Ignore(name)
+		}
+	}
+	for _, name := range sortedKeys(m.overrides.Func) {
+		if hasField(name) {
+			reverse.skip(name)
+		}
+	}
+	for _, name := range sortedKeys(m.overrides.Convert) {
+		fromName := m.overrides.Rename[name]
+		if inverse, ok := m.inverses[name]; ok {
+			reverse.Field(fromName, name)
+			reverse.ConvertWithInverse(fromName, inverse.Interface(), m.config.funcs[name].Interface())
+		} else {
+			reverse.skip(fromName)
+		}
+	}
+	return reverse
+}
+
+// Err returns the first configuration error, or the UnmappedFieldsError of
+// the configured mapping if the Option disallows unmapped fields
+func (m *Mapper[From, To]) Err() error {
+	if m.err != nil {
+		return m.err
+	}
+	return checkMapping(m.config.mapping, m.config.toType, m.config.fromType, m.opt)
+}
+
+// Map copies from to a new To, pointer types are allocated
+func (m *Mapper[From, To]) Map(from From) (To, error) {
+	var to To
+	if m.err != nil {
+		return to, m.err
+	} else if isNil(from) {
+		return to, nil
+	}
+
+	to = newValue[To]()
+	err := copier(&to, from, m.opt)
+	return to, err
+}
+
+// Explain describes what Map copies
+func (m *Mapper[From, To]) Explain() *Plan {
+	if m.config.mapping == nil {
+		return nil
+	}
+	return m.config.mapping.Plan()
+}
+
+// resolve updates the mapping after the settings changed
+func (m *Mapper[From, To]) resolve() *Mapper[From, To] {
+	if m.err != nil {
+		return m
+	}
+
+	opts := m.opt.mappingOptions()
+	opts.Overrides = &m.overrides
+	m.config.mapping = mapping.Resolve(mapping.Reflect(m.config.toType), mapping.Reflect(m.config.fromType), opts)
+	m.opt.mapper = &m.config
+	return m
+}
+
+// skip leaves the destination field name unset, reporting it as missing
+func (m *Mapper[From, To]) skip(name string) *Mapper[From, To] {
+	if _, ok := m.destField(name); !ok {
+		return m
+	}
+	m.reset(name)
+	m.overrides.Skip[name] = true
+	return m.resolve()
+}
+
+// reset removes the settings of the destination field name
+func (m *Mapper[From, To]) reset(name string) {
+	delete(m.overrides.Ignore, name)
+	delete(m.overrides.Rename, name)
+	delete(m.overrides.Func, name)
+	delete(m.overrides.Convert, name)
+	delete(m.overrides.Skip, name)
+	delete(m.config.funcs, name)
+	delete(m.inverses, name)
+}
+
+// destField returns the exported destination field name, or records an error
+func (m *Mapper[From, To]) destField(name string) (reflect.StructField, bool) {
+	if m.err != nil {
+		return reflect.StructField{}, false
+	}
+	if _, ok := mapping.FieldByName(mapping.Reflect(m.config.toType), name); !ok || !isExported(name) {
+		m.fail("unknown destination field %v", describeName(name, exportedFields(m.config.toType)))
+		return reflect.StructField{}, false
+	}
+	field, _ := m.config.toType.FieldByName(name)
+	return field, true
+}
+
+func (m *Mapper[From, To]) fail(format string, args ...interface{}) *Mapper[From, To] {
+	if m.err == nil {
+		m.err = fmt.Errorf("mapper from %v to %v: "+format, append([]interface{}{m.config.fromType, m.config.toType}, args...)...)
+	}
+	return m
+}
+
+// setFromFunc sets toField to the value fn returns for arg
+func setFromFunc(toField, arg, fn reflect.Value, opt Option) error {
+	if !arg.Type().AssignableTo(fn.Type().In(0)) {
+		arg = arg.Addr()
+	}
+
+	results := fn.Call([]reflect.Value{arg})
+	if len(results) == 2 && !results[1].IsNil() {
+		return results[1].Interface().(error)
+	}
+	if toField.CanSet() {
+		oldTo := opt.snapshot(toField)
+		if set(toField, results[0]) {
+			opt.written(results[0], toField, oldTo)
+		}
+	}
+	return nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isFunc reports whether fn is a func(in) V or func(in) (V, error)
+func isFunc(fn reflect.Value, in reflect.Type) bool {
+	if fn.Kind() != reflect.Func {
+		return false
+	}
+	fnType := fn.Type()
+	if fnType.NumIn() != 1 || !in.AssignableTo(fnType.In(0)) {
+		return false
+	}
+	return fnType.NumOut() == 1 || (fnType.NumOut() == 2 && fnType.Out(1) == errorType)
+}
+
+// settable reports whether set can set a value of fromType to toType
+func settable(toType, fromType reflect.Type) bool {
+	toType, fromType = indirectPtrType(toType), indirectPtrType(fromType)
+	return fromType.ConvertibleTo(toType) || reflect.PtrTo(toType).Implements(scannerType)
+}
+
+func indirectPtrType(reflectType reflect.Type) reflect.Type {
+	for reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+	return reflectType
+}
+
+// exportedFields returns the names of the exported fields of structType, including promoted fields
+func exportedFields(structType reflect.Type) []string {
+	var names []string
+	for _, field := range reflect.VisibleFields(structType) {
+		if field.IsExported() && !field.Anonymous {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// describeName adds the closest candidates to name
+func describeName(name string, candidates []string) string {
+	return mapping.DescribeNames([]string{name}, map[string][]string{name: mapping.SuggestNames(name, candidates)})
+}
+
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
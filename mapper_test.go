@@ -0,0 +1,251 @@
+package copier
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMapper(t *testing.T) {
+	mapper := NewMapper[User, Employee]().
+		Field("EmployeID", func(user *User) int64 { return int64(len(user.Name)) }).
+		Field("Name", "Nickname").
+		Convert("Age", func(age int32) int64 { return int64(age) + 1 }).
+		Ignore("Notes")
+	if err := mapper.Err(); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	user := User{Name: "Jinzhu", Nickname: "jinzhu", Age: 18, Notes: []string{"hello world"}}
+	employee, err := mapper.Map(user)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if employee.EmployeID != 6 {
+		t.Errorf("EmployeID should be set by the func, but got %v", employee.EmployeID)
+	}
+	if employee.Name != "jinzhu" || employee.Nickname == nil || *employee.Nickname != "jinzhu" {
+		t.Errorf("Name should be copied from Nickname, and Nickname still by name, but got %v, %v", employee.Name, employee.Nickname)
+	}
+	if employee.Age != 19 {
+		t.Errorf("Age should be converted, but got %v", employee.Age)
+	}
+	if employee.Notes != nil {
+		t.Errorf("Notes should be ignored, but got %v", employee.Notes)
+	}
+	if employee.DoubleAge != 36 {
+		t.Errorf("Other fields should be copied as usual, but got %v", employee.DoubleAge)
+	}
+}
+
+func TestMapperPtr(t *testing.T) {
+	mapper := NewMapper[*User, *Employee]().Field("EmployeID", func(user User) int64 { return 7 })
+
+	employee, err := mapper.Map(&User{Name: "Jinzhu"})
+	if err != nil || employee == nil {
+		t.Fatalf("Should allocate the destination, but got %v, %v", employee, err)
+	}
+	if employee.Name != "Jinzhu" || employee.EmployeID != 7 {
+		t.Errorf("Should copy to the destination, but got %v", employee)
+	}
+
+	if employee, err := mapper.Map(nil); employee != nil || err != nil {
+		t.Errorf("Should copy nil to nil, but got %v, %v", employee, err)
+	}
+}
+
+func TestMapperFuncError(t *testing.T) {
+	errInvalid := errors.New("invalid age")
+	mapper := NewMapper[User, Employee]().Convert("Age", func(age int32) (int64, error) {
+		if age < 0 {
+			return 0, errInvalid
+		}
+		return int64(age), nil
+	})
+
+	if _, err := mapper.Map(User{Age: -1}); err != errInvalid {
+		t.Errorf("Should return the error of the func, but got %v", err)
+	}
+	if employee, err := mapper.Map(User{Age: 3}); err != nil || employee.Age != 3 {
+		t.Errorf("Should convert the age, but got %v, %v", employee.Age, err)
+	}
+}
+
+func TestMapperConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		mapper *Mapper[User, Employee]
+		err    string
+	}{
+		{"unknown destination", NewMapper[User, Employee]().Field("EmployeId", "Name"), "unknown destination field EmployeId (did you mean EmployeID?)"},
+		{"unknown source", NewMapper[User, Employee]().Field("Name", "NickName"), "unknown source field NickName (did you mean Nickname?)"},
+		{"unexported destination", NewMapper[User, Employee]().Ignore("flags"), "unknown destination field flags"},
+		{"bad func", NewMapper[User, Employee]().Field("Name", func(string) string { return "" }), "isn't a source field name or a func"},
+		{"bad func result", NewMapper[User, Employee]().Field("Name", func(User) []int { return nil }), "can't set []int to string"},
+		{"bad convert", NewMapper[User, Employee]().Convert("Age", func(string) int64 { return 0 }), "isn't a func(int32)"},
+		{"convert without source", NewMapper[User, Employee]().Convert("EmployeID", func(int64) int64 { return 0 }), "EmployeID isn't copied from a source field"},
+		{"first error", NewMapper[User, Employee]().Ignore("Nam").Ignore("Agee"), "unknown destination field Nam"},
+	}
+
+	for _, test := range tests {
+		err := test.mapper.Err()
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%v: should return error %q, but got %v", test.name, test.err, err)
+		}
+		if _, mapErr := test.mapper.Map(User{}); mapErr != err {
+			t.Errorf("%v: Map should return the configuration error, but got %v", test.name, mapErr)
+		}
+	}
+
+	if err := NewMapper[User, []Employee]().Err(); err == nil {
+		t.Errorf("Should not map to a slice")
+	}
+}
+
+func TestMapperStrict(t *testing.T) {
+	mapper := NewMapperWithOption[User, Employee](Option{RequireAllDest: true})
+	if err := mapper.Err(); err == nil {
+		t.Errorf("Should return the missing destination fields")
+	}
+
+	mapper.Field("EmployeID", func(User) int64 { return 1 }).Ignore("FakeAge", "SuperRule")
+	if err := mapper.Err(); err != nil {
+		t.Errorf("Should not raise error once every field is set, but got %v", err)
+	}
+	if _, err := mapper.Map(User{}); err != nil {
+		t.Errorf("Should not raise error, but got %v", err)
+	}
+}
+
+func TestMapperExplain(t *testing.T) {
+	plan := NewMapper[User, Employee]().Field("EmployeID", func(User) int64 { return 1 }).Field("Name", "Nickname").Explain()
+
+	sources := map[string]FieldPlan{}
+	for _, field := range plan.Fields {
+		sources[field.Dest] = field
+	}
+	if field := sources["EmployeID"]; field.SourceKind != MemberFunc || field.Conversion != ConversionFunc {
+		t.Errorf("EmployeID should be set by a func, but got %+v", field)
+	}
+	if field := sources["Name"]; field.Source != "Nickname" {
+		t.Errorf("Name should be copied from Nickname, but got %+v", field)
+	}
+}
+
+func TestMapperConcurrent(t *testing.T) {
+	mapper := NewMapper[User, Employee]().Convert("Age", func(age int32) int64 { return int64(age) * 10 })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(age int32) {
+			defer wg.Done()
+			employee, err := mapper.Map(User{Age: age})
+			if err != nil || employee.Age != int64(age)*10 {
+				t.Errorf("Should map concurrently, but got %v, %v", employee.Age, err)
+			}
+		}(int32(i))
+	}
+	wg.Wait()
+}
+
+func TestMapperReverse(t *testing.T) {
+	mapper := NewMapper[User, Employee]().
+		Field("Name", "Nickname").
+		ConvertWithInverse("Age", func(age int32) int64 { return int64(age) * 2 }, func(age int64) int32 { return int32(age / 2) }).
+		Ignore("Notes")
+	reverse := mapper.Reverse()
+	if err := reverse.Err(); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	nickname := "jinzhu"
+	user, err := reverse.Map(Employee{Name: "Jinzhu", Nickname: &nickname, Age: 36, Notes: []string{"hello world"}})
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if user.Nickname != "Jinzhu" {
+		t.Errorf("Nickname should be copied back from Name, but got %v", user.Nickname)
+	}
+	if user.Age != 18 {
+		t.Errorf("Age should be converted with the inverse, but got %v", user.Age)
+	}
+	if user.Notes != nil {
+		t.Errorf("Notes should be ignored, but got %v", user.Notes)
+	}
+
+	// getters and setters swap roles
+	accessors := NewMapperWithOption[reverseUser, reverseProfile](Option{GetterPrefix: "Get", SetterPrefix: "Set", RequireAllDest: true})
+	profile, err := accessors.Map(reverseUser{Nickname: "jinzhu", score: 18})
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if profile.Score != 18 || profile.nickname != "jinzhu" {
+		t.Fatalf("Should copy from the getter and to the setter, but got %+v", profile)
+	}
+
+	restored, err := accessors.Reverse().Map(profile)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if restored.score != 18 {
+		t.Errorf("Score should be copied back to the setter, but got %v", restored.score)
+	}
+	if restored.Nickname != "jinzhu" {
+		t.Errorf("Nickname should be copied back from the getter, but got %v", restored.Nickname)
+	}
+}
+
+type reverseUser struct {
+	Nickname string
+	score    int
+}
+
+func (user *reverseUser) GetScore() int {
+	return user.score
+}
+
+func (user *reverseUser) SetScore(score int) {
+	user.score = score
+}
+
+type reverseProfile struct {
+	Score    int
+	nickname string
+}
+
+func (profile *reverseProfile) GetNickname() string {
+	return profile.nickname
+}
+
+func (profile *reverseProfile) SetNickname(nickname string) {
+	profile.nickname = nickname
+}
+
+func TestMapperReverseOneWay(t *testing.T) {
+	mapper := NewMapperWithOption[User, Employee](Option{RequireAllDest: true}).
+		Field("Name", func(user User) string { return user.Nickname }).
+		Convert("Age", func(age int32) int64 { return int64(age) })
+
+	reverse := mapper.Reverse()
+	err, ok := reverse.Err().(*UnmappedFieldsError)
+	if !ok {
+		t.Fatalf("Should report the one way fields as unmapped, but got %v", reverse.Err())
+	}
+	missing := strings.Join(err.MissingDest, ",")
+	if !strings.Contains(missing, "Name") || !strings.Contains(missing, "Age") {
+		t.Errorf("Name and Age should be missing, but got %v", missing)
+	}
+
+	if err := NewMapper[User, []Employee]().Reverse().Err(); err == nil {
+		t.Errorf("Should keep the configuration error")
+	}
+}
+
+func TestMapperConvertWithInverseErrors(t *testing.T) {
+	mapper := NewMapper[User, Employee]().ConvertWithInverse("Age", func(age int32) int64 { return 0 }, func(age string) int32 { return 0 })
+	if err := mapper.Err(); err == nil || !strings.Contains(err.Error(), "inverse") {
+		t.Errorf("Should reject the inverse, but got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package copier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+// typeMappings caches resolved mappings by typeMappingKey
+var typeMappings sync.Map
+
+type typeMappingKey struct {
+	toType, fromType reflect.Type
+	opts             mapping.Options
+}
+
+// mapTypes resolves which source fields and getters are copied to which
+// destination fields and setters
+func mapTypes(toType, fromType reflect.Type, opt Option) *mapping.Mapping {
+	key := typeMappingKey{toType: toType, fromType: fromType, opts: opt.mappingOptions()}
+	if typeMapping, ok := typeMappings.Load(key); ok {
+		return typeMapping.(*mapping.Mapping)
+	}
+
+	typeMapping := mapping.Resolve(mapping.Reflect(toType), mapping.Reflect(fromType), key.opts)
+	typeMappings.Store(key, typeMapping)
+	return typeMapping
+}
+
+// mappingOptions returns the options changing how types are mapped
+func (opt Option) mappingOptions() mapping.Options {
+	return mapping.Options{
+		TagMatch:     opt.TagMatch,
+		Unflatten:    opt.Unflatten,
+		GetterPrefix: opt.GetterPrefix,
+		SetterPrefix: opt.SetterPrefix,
+	}
+}
+
+// checkMapping returns an AmbiguousFieldsError if typeMapping selects
+// ambiguous fields, or an UnmappedFieldsError if opt disallows its unmapped fields
+func checkMapping(typeMapping *mapping.Mapping, toType, fromType reflect.Type, opt Option) error {
+	// typeMapping is cached, so errors get copies of its names
+	if len(typeMapping.AmbiguousSource) > 0 || len(typeMapping.AmbiguousDest) > 0 {
+		return &AmbiguousFieldsError{
+			FromType: fromType,
+			ToType:   toType,
+			Source:   append([]string(nil), typeMapping.AmbiguousSource...),
+			Dest:     append([]string(nil), typeMapping.AmbiguousDest...),
+		}
+	}
+
+	unknownSource := opt.DisallowUnknownSource && len(typeMapping.UnknownSource) > 0
+	missingDest := opt.RequireAllDest && len(typeMapping.MissingDest) > 0
+	if !unknownSource && !missingDest {
+		return nil
+	}
+
+	err := &UnmappedFieldsError{FromType: fromType, ToType: toType, Suggestions: map[string][]string{}}
+	if unknownSource {
+		err.UnknownSource = append([]string(nil), typeMapping.UnknownSource...)
+	}
+	if missingDest {
+		err.MissingDest = append([]string(nil), typeMapping.MissingDest...)
+	}
+
+	for _, names := range [][]string{err.UnknownSource, err.MissingDest} {
+		for _, name := range names {
+			if suggestions, ok := typeMapping.Suggestions[name]; ok {
+				err.Suggestions[name] = append([]string(nil), suggestions...)
+			}
+		}
+	}
+	return err
+}
+
+// UnmappedFieldsError is returned in strict mode when source fields have no
+// target or destination fields get no value
+type UnmappedFieldsError struct {
+	FromType, ToType reflect.Type
+	UnknownSource    []string // source fields without a target field or setter
+	MissingDest      []string // destination fields without a source field or getter
+
+	// Suggestions lists the closest names on the other type for unmapped fields
+	Suggestions map[string][]string
+}
+
+func (err *UnmappedFieldsError) Error() string {
+	var problems []string
+	if len(err.UnknownSource) > 0 {
+		problems = append(problems, "unknown source fields "+mapping.DescribeNames(err.UnknownSource, err.Suggestions))
+	}
+	if len(err.MissingDest) > 0 {
+		problems = append(problems, "missing destination fields "+mapping.DescribeNames(err.MissingDest, err.Suggestions))
+	}
+	return fmt.Sprintf("copy from %v to %v: %v", err.FromType, err.ToType, strings.Join(problems, "; "))
+}
+
+// AmbiguousFieldsError is returned when a field to copy is promoted from
+// several embedded structs at the same depth, so Go can't select it
+type AmbiguousFieldsError struct {
+	FromType, ToType reflect.Type
+	Source           []string // ambiguous source fields
+	Dest             []string // ambiguous destination fields
+}
+
+func (err *AmbiguousFieldsError) Error() string {
+	var problems []string
+	if len(err.Source) > 0 {
+		problems = append(problems, "ambiguous source fields "+strings.Join(err.Source, ", "))
+	}
+	if len(err.Dest) > 0 {
+		problems = append(problems, "ambiguous destination fields "+strings.Join(err.Dest, ", "))
+	}
+	return fmt.Sprintf("copy from %v to %v: %v", err.FromType, err.ToType, strings.Join(problems, "; "))
+}
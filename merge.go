@@ -0,0 +1,165 @@
+package copier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Conflict is a field changed differently by theirs and ours in Merge3, the
+// values are converted to the destination field type
+type Conflict struct {
+	Path     string // path of the destination field, like Option.OnField's
+	Base     interface{}
+	Theirs   interface{}
+	Ours     interface{}
+	Resolved bool // whether the MergeResolver set the field
+}
+
+// MergeResolver returns the value a conflicting field is set to, or false to
+// leave the field unchanged
+type MergeResolver func(conflict Conflict) (value interface{}, ok bool)
+
+// Merge resolvers keeping one side of every conflict
+var (
+	PreferOurs   MergeResolver = func(conflict Conflict) (interface{}, bool) { return conflict.Ours, true }
+	PreferTheirs MergeResolver = func(conflict Conflict) (interface{}, bool) { return conflict.Theirs, true }
+)
+
+// Merge3 merges the changes theirs and ours made to base into the struct dst
+// points to. base, theirs and ours are copied to the type of dst like Copy
+// does, so they may be of any type Copy can copy from. Fields changed by one
+// side, or the same way by both, are set, fields changed differently are left
+// unchanged and returned as conflicts.
+func Merge3(dst, base, theirs, ours interface{}) ([]Conflict, error) {
+	return Merge3WithResolver(dst, base, theirs, ours, nil)
+}
+
+// Merge3WithResolver is Merge3 setting conflicting fields to the value resolve returns
+func Merge3WithResolver(dst, base, theirs, ours interface{}, resolve MergeResolver) ([]Conflict, error) {
+	to := indirect(reflect.ValueOf(dst))
+	if !to.CanAddr() || to.Kind() != reflect.Struct {
+		return nil, errors.New("merge destination must be a pointer to a struct")
+	}
+
+	var versions [3]reflect.Value
+	for i, from := range []interface{}{base, theirs, ours} {
+		versions[i] = reflect.New(to.Type()).Elem()
+		if err := Copy(versions[i].Addr().Interface(), from); err != nil {
+			return nil, err
+		}
+	}
+
+	merger := merger{resolve: resolve}
+	if err := merger.merge(to, versions[0], versions[1], versions[2], ""); err != nil {
+		return nil, err
+	}
+	return merger.conflicts, nil
+}
+
+type merger struct {
+	resolve   MergeResolver
+	conflicts []Conflict
+}
+
+// merge merges the exported fields of the structs base, theirs and ours into to
+func (merger *merger) merge(to, base, theirs, ours reflect.Value, path string) error {
+	for _, field := range reflect.VisibleFields(to.Type()) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		var (
+			baseField   = fieldByIndex(base, field.Index)
+			theirsField = fieldByIndex(theirs, field.Index)
+			oursField   = fieldByIndex(ours, field.Index)
+		)
+
+		// structs are merged field by field
+		if isMergedStruct(field.Type) && !isNilPtr(baseField) && !isNilPtr(theirsField) && !isNilPtr(oursField) {
+			toField := allocFieldByIndex(to, field.Index)
+			if !toField.IsValid() {
+				continue
+			}
+			if toField.Kind() == reflect.Ptr && toField.IsNil() {
+				toField.Set(reflect.New(toField.Type().Elem()))
+			}
+			if err := merger.merge(indirect(toField), indirect(baseField), indirect(theirsField), indirect(oursField), fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var (
+			theirsChanged = !reflect.DeepEqual(baseField.Interface(), theirsField.Interface())
+			oursChanged   = !reflect.DeepEqual(baseField.Interface(), oursField.Interface())
+		)
+		switch {
+		case !theirsChanged && !oursChanged:
+		case !oursChanged:
+			if toField := allocFieldByIndex(to, field.Index); toField.IsValid() {
+				toField.Set(theirsField)
+			}
+		case !theirsChanged || reflect.DeepEqual(theirsField.Interface(), oursField.Interface()):
+			if toField := allocFieldByIndex(to, field.Index); toField.IsValid() {
+				toField.Set(oursField)
+			}
+		default:
+			conflict := Conflict{Path: fieldPath, Base: snapshot(baseField), Theirs: snapshot(theirsField), Ours: snapshot(oursField)}
+			if merger.resolve != nil {
+				if value, ok := merger.resolve(conflict); ok {
+					toField := allocFieldByIndex(to, field.Index)
+					if !toField.IsValid() || !setValue(toField, value) {
+						return fmt.Errorf("merge %v: can't set %T to %v", fieldPath, value, toField.Type())
+					}
+					conflict.Resolved = true
+				}
+			}
+			merger.conflicts = append(merger.conflicts, conflict)
+		}
+	}
+	return nil
+}
+
+// isMergedStruct reports whether fields of fieldType are merged field by field,
+// structs without exported fields like time.Time are merged as a whole
+func isMergedStruct(fieldType reflect.Type) bool {
+	fieldType = indirectPtrType(fieldType)
+	if fieldType.Kind() != reflect.Struct {
+		return false
+	}
+	for _, field := range reflect.VisibleFields(fieldType) {
+		if field.IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func isNilPtr(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// fieldByIndex returns the promoted field of the struct v, or its zero value
+// when an embedded pointer is nil
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	field, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Zero(v.Type().FieldByIndex(index).Type)
+	}
+	return field
+}
+
+// setValue sets to to value, converting it like Copy
+func setValue(to reflect.Value, value interface{}) bool {
+	if value == nil {
+		to.Set(reflect.Zero(to.Type()))
+		return true
+	}
+	return set(to, reflect.ValueOf(value))
+}
@@ -0,0 +1,121 @@
+package copier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type mergeBase struct {
+	ID int64
+}
+
+type mergeAddress struct {
+	City, Zip string
+}
+
+type mergeModel struct {
+	mergeBase
+	Name    string
+	Age     int
+	Status  string
+	Tags    []string
+	Address *mergeAddress
+}
+
+type mergeDTO struct {
+	ID      int64
+	Name    string
+	Age     int32
+	Status  string
+	Tags    []string
+	Address struct{ City, Zip string }
+}
+
+func TestMerge3(t *testing.T) {
+	base := mergeDTO{ID: 1, Name: "jinzhu", Age: 18, Status: "draft", Tags: []string{"a"}}
+	base.Address.City = "Shanghai"
+
+	theirs := base
+	theirs.Name = "Jinzhu"
+	theirs.Status = "published"
+	theirs.Address.Zip = "200000"
+
+	ours := base
+	ours.Age = 19
+	ours.Status = "archived"
+	ours.Tags = []string{"a", "b"}
+	ours.Address.Zip = "200000"
+
+	var dst mergeModel
+	if err := Copy(&dst, &base); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	conflicts, err := Merge3(&dst, base, theirs, &ours)
+	if err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	if dst.ID != 1 || dst.Name != "Jinzhu" || dst.Age != 19 || !reflect.DeepEqual(dst.Tags, []string{"a", "b"}) {
+		t.Errorf("Should apply the changes of one side, but got %+v", dst)
+	}
+	if dst.Address.City != "Shanghai" || dst.Address.Zip != "200000" {
+		t.Errorf("Should apply the same change of both sides, but got %+v", dst.Address)
+	}
+	if dst.Status != "draft" {
+		t.Errorf("Should leave conflicts unchanged, but got %v", dst.Status)
+	}
+
+	expected := []Conflict{{Path: "Status", Base: "draft", Theirs: "published", Ours: "archived"}}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("Should return the conflicts %+v, but got %+v", expected, conflicts)
+	}
+}
+
+func TestMerge3WithResolver(t *testing.T) {
+	base := mergeDTO{Status: "draft", Age: 1}
+	theirs := mergeDTO{Status: "published", Age: 2}
+	ours := mergeDTO{Status: "archived", Age: 3}
+
+	var dst mergeModel
+	conflicts, err := Merge3WithResolver(&dst, base, theirs, ours, PreferTheirs)
+	if err != nil || len(conflicts) != 2 || !conflicts[0].Resolved {
+		t.Fatalf("Should resolve the conflicts, but got %+v, %v", conflicts, err)
+	}
+	if dst.Status != "published" || dst.Age != 2 {
+		t.Errorf("Should prefer theirs, but got %+v", dst)
+	}
+
+	dst = mergeModel{}
+	_, err = Merge3WithResolver(&dst, base, theirs, ours, func(conflict Conflict) (interface{}, bool) {
+		if conflict.Path == "Age" {
+			return int32(10), true
+		}
+		return nil, false
+	})
+	if err != nil || dst.Age != 10 || dst.Status != "" {
+		t.Errorf("Should use the custom resolver, but got %+v, %v", dst, err)
+	}
+
+	_, err = Merge3WithResolver(&dst, base, theirs, ours, func(conflict Conflict) (interface{}, bool) {
+		return []int{1}, true
+	})
+	if err == nil {
+		t.Errorf("Should not set values of other types")
+	}
+
+	if _, err := Merge3(dst, base, theirs, ours); err == nil {
+		t.Errorf("Should not merge into a value")
+	}
+}
+
+func TestMerge3PreferOurs(t *testing.T) {
+	var dst mergeModel
+	conflicts, err := Merge3WithResolver(&dst, mergeDTO{ID: 1}, mergeDTO{ID: 2}, mergeDTO{ID: 3}, PreferOurs)
+	if err != nil || len(conflicts) != 1 || conflicts[0].Path != "ID" {
+		t.Fatalf("Should return the conflict of the embedded field, but got %+v, %v", conflicts, err)
+	}
+	if dst.ID != 3 {
+		t.Errorf("Should prefer ours, but got %+v", dst.mergeBase)
+	}
+}
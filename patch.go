@@ -0,0 +1,202 @@
+package copier
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+// ApplyMergePatch applies the JSON Merge Patch (RFC 7396) patch to the struct
+// dst points to. Keys are matched to fields by their json tag names, falling
+// back to the field names, like Copy with Option{TagMatch: "json"}. Present
+// keys overwrite their fields, objects are merged into struct fields, null
+// clears a field to its zero value and absent keys are left untouched.
+// Unknown keys are ignored.
+func ApplyMergePatch(dst interface{}, patch []byte) error {
+	return ApplyMergePatchWithOption(dst, patch, Option{TagMatch: "json"})
+}
+
+// ApplyMergePatchWithOption is ApplyMergePatch matching keys with
+// opt.TagMatch. Unknown keys are an error with opt.DisallowUnknownSource, and
+// opt.OnField is called for every field written.
+func ApplyMergePatchWithOption(dst interface{}, patch []byte, opt Option) error {
+	to := indirect(reflect.ValueOf(dst))
+	if !to.CanAddr() || to.Kind() != reflect.Struct {
+		return errors.New("merge patch destination must be a pointer to a struct")
+	}
+	return mergePatch(to, patch, opt)
+}
+
+// mergePatch merges the JSON object patch into the struct to
+func mergePatch(to reflect.Value, patch []byte, opt Option) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+		return fmt.Errorf("merge patch for %v must be a JSON object", to.Type())
+	}
+
+	var (
+		toType  = mapping.Reflect(to.Type())
+		keys    = make([]string, 0, len(members))
+		unknown []string
+	)
+	for key := range members {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, ok := mapping.FieldNameByTag(toType, opt.TagMatch, key)
+		toField := reflect.Value{}
+		if ok {
+			// fields promoted through nil unexported embedded pointers can't be set
+			toField = allocFieldByName(to, name)
+		}
+		if !toField.IsValid() {
+			unknown = append(unknown, key)
+			continue
+		}
+		if err := mergeField(toField, members[key], opt.field(name)); err != nil {
+			return err
+		}
+	}
+
+	if len(unknown) > 0 && opt.DisallowUnknownSource {
+		candidates := patchKeys(to.Type(), opt.TagMatch)
+		suggestions := map[string][]string{}
+		for _, key := range unknown {
+			suggestions[key] = mapping.SuggestNames(key, candidates)
+		}
+		return fmt.Errorf("merge patch for %v: unknown keys %v", to.Type(), mapping.DescribeNames(unknown, suggestions))
+	}
+	return nil
+}
+
+// mergeField merges the JSON value patch into toField
+func mergeField(toField reflect.Value, patch json.RawMessage, opt Option) error {
+	oldTo := opt.snapshot(toField)
+
+	if bytes.Equal(bytes.TrimSpace(patch), []byte("null")) {
+		toField.Set(reflect.Zero(toField.Type()))
+		opt.written(reflect.ValueOf(nil), toField, oldTo)
+		return nil
+	}
+
+	// objects are merged into structs, other values replace the field
+	if fieldType := indirectPtrType(toField.Type()); fieldType.Kind() == reflect.Struct && isObject(patch) && !decodesItself(fieldType) {
+		if toField.Kind() == reflect.Ptr && toField.IsNil() {
+			toField.Set(reflect.New(fieldType))
+		}
+		return mergePatch(indirect(toField), patch, opt)
+	}
+
+	// objects are merged into maps key by key
+	if isMergedMap(toField.Type()) && isObject(patch) {
+		return mergeMapPatch(toField, patch, opt)
+	}
+
+	value := reflect.New(toField.Type())
+	if scanner, ok := value.Interface().(sql.Scanner); ok && !decodesItself(toField.Type()) {
+		// sql.Null* types scan the plain JSON value
+		var src interface{}
+		if err := json.Unmarshal(patch, &src); err != nil {
+			return fmt.Errorf("merge patch %v: %w", opt.path, err)
+		}
+		if err := scanner.Scan(src); err != nil {
+			return fmt.Errorf("merge patch %v: %w", opt.path, err)
+		}
+	} else if err := json.Unmarshal(patch, value.Interface()); err != nil {
+		return fmt.Errorf("merge patch %v: %w", opt.path, err)
+	}
+
+	toField.Set(value.Elem())
+	opt.written(value.Elem(), toField, oldTo)
+	return nil
+}
+
+// mergeMapPatch merges the JSON object patch into a copy of the map toField,
+// null members delete their keys and objects are merged into the values
+func mergeMapPatch(toField reflect.Value, patch json.RawMessage, opt Option) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil {
+		return fmt.Errorf("merge patch %v: %w", opt.path, err)
+	}
+
+	var (
+		mapType = toField.Type()
+		merged  = reflect.MakeMapWithSize(mapType, toField.Len()+len(members))
+		keys    = make([]string, 0, len(members))
+	)
+	for iter := toField.MapRange(); iter.Next(); {
+		merged.SetMapIndex(iter.Key(), iter.Value())
+	}
+	for key := range members {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// the map is reported as a whole
+	elemOpt := opt
+	elemOpt.OnField = nil
+	for _, key := range keys {
+		mapKey := reflect.ValueOf(key).Convert(mapType.Key())
+		if bytes.Equal(bytes.TrimSpace(members[key]), []byte("null")) {
+			merged.SetMapIndex(mapKey, reflect.Value{})
+			continue
+		}
+
+		elem := reflect.New(mapType.Elem()).Elem()
+		if existing := merged.MapIndex(mapKey); existing.IsValid() {
+			elem.Set(deepCopy(existing))
+		}
+		if err := mergeField(elem, members[key], elemOpt.field(key)); err != nil {
+			return err
+		}
+		merged.SetMapIndex(mapKey, elem)
+	}
+
+	oldTo := opt.snapshot(toField)
+	toField.Set(merged)
+	opt.written(merged, toField, oldTo)
+	return nil
+}
+
+// isMergedMap reports whether JSON objects are merged into mapType key by key
+func isMergedMap(mapType reflect.Type) bool {
+	return mapType.Kind() == reflect.Map && mapType.Key().Kind() == reflect.String && !decodesItself(mapType)
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodesItself reports whether a pointer to reflectType implements json.Unmarshaler
+func decodesItself(reflectType reflect.Type) bool {
+	return reflect.PtrTo(reflectType).Implements(unmarshalerType)
+}
+
+func isObject(patch json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{"))
+}
+
+// patchKeys returns the keys matching the fields of structType
+func patchKeys(structType reflect.Type, tagKey string) []string {
+	var keys []string
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := strings.Split(field.Tag.Get(tagKey), ",")[0]
+		if tagKey == "" || name == "" {
+			name = field.Name
+		}
+		if name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,176 @@
+package copier
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type patchAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type patchUser struct {
+	Name     string         `json:"name"`
+	Nickname *string        `json:"nickname"`
+	Age      int            `json:"age"`
+	Email    sql.NullString `json:"email"`
+	Score    sql.NullInt64  `json:"score"`
+	Address  *patchAddress  `json:"address"`
+	Tags     []string       `json:"tags"`
+	Birthday time.Time      `json:"birthday"`
+	Password string         `json:"-"`
+	Role     string
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	nickname := "jinzhu"
+	user := patchUser{
+		Name:     "Jinzhu",
+		Nickname: &nickname,
+		Age:      18,
+		Email:    sql.NullString{String: "jinzhu@example.org", Valid: true},
+		Address:  &patchAddress{City: "Shanghai", Zip: "200000"},
+		Tags:     []string{"a", "b"},
+		Password: "secret",
+		Role:     "Admin",
+	}
+
+	patch := `{
+		"nickname": null,
+		"age": 20,
+		"email": null,
+		"score": 5,
+		"address": {"city": "Beijing"},
+		"tags": ["c"],
+		"birthday": "2020-01-02T00:00:00Z",
+		"Password": "leaked",
+		"Role": "Dev"
+	}`
+	if err := ApplyMergePatch(&user, []byte(patch)); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	if user.Name != "Jinzhu" {
+		t.Errorf("Absent keys should be untouched, but got %v", user.Name)
+	}
+	if user.Nickname != nil || user.Email.Valid {
+		t.Errorf("null should clear fields, but got %v, %v", user.Nickname, user.Email)
+	}
+	if user.Age != 20 || user.Role != "Dev" || len(user.Tags) != 1 || user.Tags[0] != "c" {
+		t.Errorf("Present keys should overwrite, but got %+v", user)
+	}
+	if !user.Score.Valid || user.Score.Int64 != 5 {
+		t.Errorf("Nullable fields should scan the value, but got %v", user.Score)
+	}
+	if user.Address.City != "Beijing" || user.Address.Zip != "200000" {
+		t.Errorf("Objects should be merged, but got %+v", user.Address)
+	}
+	if !user.Birthday.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Types decoding JSON themselves should be replaced, but got %v", user.Birthday)
+	}
+	if user.Password != "secret" {
+		t.Errorf("Fields excluded from json should be untouched, but got %v", user.Password)
+	}
+}
+
+func TestApplyMergePatchNilStruct(t *testing.T) {
+	var user patchUser
+	if err := ApplyMergePatch(&user, []byte(`{"address": {"city": "Beijing"}}`)); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if user.Address == nil || user.Address.City != "Beijing" {
+		t.Errorf("Should allocate nil struct pointers, but got %+v", user.Address)
+	}
+
+	type located struct {
+		*patchAddress
+		Name string `json:"name"`
+	}
+	var place located
+	if err := ApplyMergePatch(&place, []byte(`{"city": "Beijing"}`)); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if place.patchAddress != nil {
+		t.Errorf("Should leave unexported nil embedded pointers nil, but got %+v", place.patchAddress)
+	}
+}
+
+func TestApplyMergePatchMaps(t *testing.T) {
+	type Settings struct {
+		Labels   map[string]string         `json:"labels"`
+		Sections map[string]map[string]int `json:"sections"`
+		Contacts map[string]patchAddress   `json:"contacts"`
+		Extra    map[string]interface{}    `json:"extra"`
+	}
+
+	labels := map[string]string{"keep": "1", "drop": "2"}
+	settings := Settings{
+		Labels:   labels,
+		Sections: map[string]map[string]int{"a": {"x": 1, "y": 2}},
+		Contacts: map[string]patchAddress{"home": {City: "Shanghai", Zip: "200000"}},
+	}
+
+	patch := `{
+		"labels": {"drop": null, "new": "3"},
+		"sections": {"a": {"y": null, "z": 3}, "b": {"x": 1}},
+		"contacts": {"home": {"city": "Beijing"}},
+		"extra": {"a": null, "b": 1}
+	}`
+	if err := ApplyMergePatch(&settings, []byte(patch)); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	if !reflect.DeepEqual(settings.Labels, map[string]string{"keep": "1", "new": "3"}) {
+		t.Errorf("Should merge maps key by key and delete null keys, but got %v", settings.Labels)
+	}
+	if !reflect.DeepEqual(labels, map[string]string{"keep": "1", "drop": "2"}) {
+		t.Errorf("Should not change the original map, but got %v", labels)
+	}
+	if !reflect.DeepEqual(settings.Sections, map[string]map[string]int{"a": {"x": 1, "z": 3}, "b": {"x": 1}}) {
+		t.Errorf("Should merge nested maps, but got %v", settings.Sections)
+	}
+	if home := settings.Contacts["home"]; home.City != "Beijing" || home.Zip != "200000" {
+		t.Errorf("Should merge objects into struct values, but got %+v", home)
+	}
+	if !reflect.DeepEqual(settings.Extra, map[string]interface{}{"b": 1.0}) {
+		t.Errorf("Should allocate nil maps, but got %v", settings.Extra)
+	}
+}
+
+func TestApplyMergePatchErrors(t *testing.T) {
+	var user patchUser
+	if err := ApplyMergePatch(user, []byte(`{}`)); err == nil {
+		t.Errorf("Should not patch a value")
+	}
+	if err := ApplyMergePatch(&user, []byte(`[1]`)); err == nil {
+		t.Errorf("Should only apply objects")
+	}
+	if err := ApplyMergePatch(&user, []byte(`{"age": "old"}`)); err == nil || !strings.Contains(err.Error(), "Age") {
+		t.Errorf("Should return the path of invalid values, but got %v", err)
+	}
+	if err := ApplyMergePatch(&user, []byte(`{"unknown": 1}`)); err != nil {
+		t.Errorf("Should ignore unknown keys, but got %v", err)
+	}
+
+	err := ApplyMergePatchWithOption(&user, []byte(`{"nam": "x", "unknown": 1}`), Option{TagMatch: "json", DisallowUnknownSource: true})
+	if err == nil || err.Error() != "merge patch for copier.patchUser: unknown keys nam (did you mean name?), unknown" {
+		t.Errorf("Should return the unknown keys, but got %v", err)
+	}
+}
+
+func TestApplyMergePatchOnField(t *testing.T) {
+	user := patchUser{Address: &patchAddress{City: "Shanghai"}}
+
+	var paths []string
+	err := ApplyMergePatchWithOption(&user, []byte(`{"age": 1, "address": {"zip": "100000"}}`), Option{
+		TagMatch: "json",
+		OnField:  func(path string, from, to reflect.Value, oldTo interface{}) { paths = append(paths, path) },
+	})
+	if err != nil || strings.Join(paths, ",") != "Address.Zip,Age" {
+		t.Errorf("Should report written fields, but got %v, %v", paths, err)
+	}
+}
@@ -0,0 +1,196 @@
+package copier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/smw-104/copier/internal/mapping"
+)
+
+// CopyPaths copies only the destination fields named by the dotted paths,
+// like a protobuf FieldMask update. Paths into slices apply to every element,
+// e.g. "Orders.Status". Other fields are left untouched, and paths that aren't
+// destination fields are an error.
+func CopyPaths(toValue interface{}, fromValue interface{}, paths []string) error {
+	return CopyPathsWithOption(toValue, fromValue, paths, Option{})
+}
+
+// CopyPathsWithOption is CopyPaths with options
+func CopyPathsWithOption(toValue interface{}, fromValue interface{}, paths []string, opt Option) error {
+	var (
+		from = indirect(reflect.ValueOf(fromValue))
+		to   = indirect(reflect.ValueOf(toValue))
+	)
+
+	if !to.CanAddr() || to.Kind() != reflect.Struct {
+		return errors.New("copy paths to value must be a pointer to a struct")
+	}
+
+	mask, err := parsePaths(to.Type(), paths)
+	if err != nil {
+		return err
+	}
+
+	if !from.IsValid() || from.Kind() != reflect.Struct {
+		return nil
+	}
+	return copyPaths(to, from, mask, opt)
+}
+
+// pathMask is a tree of destination field names, fields copied whole have no children
+type pathMask map[string]pathMask
+
+// lookup returns the children of the dotted path in mask, and whether the
+// path is in it. Paths below a field copied whole have no children.
+func (mask pathMask) lookup(path string) (pathMask, bool) {
+	for _, name := range strings.Split(path, ".") {
+		children, ok := mask[name]
+		if !ok {
+			return nil, false
+		}
+		if children == nil {
+			return nil, true
+		}
+		mask = children
+	}
+	return mask, true
+}
+
+// parsePaths returns the mask of paths, which must be fields of toType
+func parsePaths(toType reflect.Type, paths []string) (pathMask, error) {
+	var (
+		mask        = pathMask{}
+		unknown     []string
+		suggestions = map[string][]string{}
+	)
+
+	for _, path := range paths {
+		var (
+			current  = mask
+			lastType = toType
+			names    = strings.Split(path, ".")
+		)
+
+		for i, name := range names {
+			structType := indirectType(lastType)
+			if structType.Kind() != reflect.Struct {
+				unknown = append(unknown, path)
+				break
+			}
+			if field, ok := mapping.FieldByName(mapping.Reflect(structType), name); !ok || !field.Exported {
+				unknown = append(unknown, path)
+				for _, suggestion := range mapping.SuggestNames(name, exportedFields(structType)) {
+					suggestions[path] = append(suggestions[path], strings.Join(append(names[:i:i], suggestion), "."))
+				}
+				break
+			}
+
+			structField, _ := structType.FieldByName(name)
+			lastType = structField.Type
+
+			children, ok := current[name]
+			if i == len(names)-1 {
+				// the whole field is copied
+				current[name] = nil
+			} else if !ok || children != nil {
+				if children == nil {
+					children = pathMask{}
+					current[name] = children
+				}
+				current = children
+			} else {
+				// a parent is already copied whole
+				break
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("copy paths to %v: unknown paths %v", toType, mapping.DescribeNames(unknown, suggestions))
+	}
+	return mask, nil
+}
+
+// copyPaths copies the fields of mask from the struct from to the struct to
+func copyPaths(to, from reflect.Value, mask pathMask, opt Option) error {
+	if !from.CanAddr() {
+		// make source addressable so methods with pointer receivers can be called
+		addressable := reflect.New(from.Type()).Elem()
+		addressable.Set(from)
+		from = addressable
+	}
+
+	typeMapping := mapTypes(to.Type(), from.Type(), opt)
+	for _, field := range typeMapping.Fields {
+		if field.ToField == "" || field.Conversion == mapping.ConversionNone {
+			continue
+		}
+		children, ok := mask.lookup(field.ToField)
+		if !ok {
+			continue
+		}
+
+		if children == nil {
+			if err := copyMapped(to, from, field, nil, opt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var fromField reflect.Value
+		if field.FromMethod != "" {
+			if nilReceiver(from, field.FromMethod) {
+				continue
+			}
+			var err error
+			if fromField, err = callGetter(from.Addr().MethodByName(field.FromMethod), opt.field(field.ToField)); err != nil {
+				return err
+			}
+		} else {
+			fromField = fieldByPath(from, field.FromField)
+		}
+		toField := allocFieldByPath(to, field.ToField)
+		if !fromField.IsValid() || !toField.IsValid() {
+			continue
+		}
+		if err := copyNestedPaths(toField, fromField, children, opt.field(field.ToField)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyNestedPaths copies the fields of mask from fromField to the struct or
+// slice of structs toField, nil sources are copied as zero values
+func copyNestedPaths(toField, fromField reflect.Value, mask pathMask, opt Option) error {
+	if toField.Kind() == reflect.Ptr {
+		if toField.IsNil() {
+			toField.Set(reflect.New(toField.Type().Elem()))
+		}
+		toField = toField.Elem()
+	}
+
+	fromType := indirectPtrType(fromField.Type())
+	if fromField = indirect(fromField); !fromField.IsValid() {
+		fromField = reflect.New(fromType).Elem()
+	}
+
+	switch {
+	case toField.Kind() == reflect.Struct && fromField.Kind() == reflect.Struct:
+		return copyPaths(toField, fromField, mask, opt)
+	case toField.Kind() == reflect.Slice && fromField.Kind() == reflect.Slice:
+		for i := 0; i < fromField.Len(); i++ {
+			if i >= toField.Len() {
+				toField.Set(reflect.Append(toField, reflect.Zero(toField.Type().Elem())))
+			}
+			elemOpt := opt
+			elemOpt.path = fmt.Sprintf("%v[%d]", opt.path, i)
+			if err := copyNestedPaths(toField.Index(i), fromField.Index(i), mask, elemOpt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package copier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type pathsAddress struct {
+	City, Zip string
+}
+
+type pathsOrder struct {
+	ID     int64
+	Status string
+}
+
+type pathsUser struct {
+	Name    string
+	Age     int
+	Address *pathsAddress
+	Orders  []pathsOrder
+}
+
+type pathsUpdate struct {
+	Name    string
+	Age     int32
+	Address pathsAddress
+	Orders  []*pathsOrder
+}
+
+func TestCopyPaths(t *testing.T) {
+	user := pathsUser{
+		Name:    "jinzhu",
+		Age:     18,
+		Address: &pathsAddress{City: "Shanghai", Zip: "200000"},
+		Orders:  []pathsOrder{{ID: 1, Status: "pending"}, {ID: 2, Status: "pending"}},
+	}
+	update := pathsUpdate{
+		Name:    "Jinzhu",
+		Age:     20,
+		Address: pathsAddress{City: "Beijing", Zip: "100000"},
+		Orders:  []*pathsOrder{{ID: 10, Status: "shipped"}, {ID: 20, Status: "paid"}},
+	}
+
+	if err := CopyPaths(&user, &update, []string{"Name", "Address.City", "Orders.Status"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+
+	expected := pathsUser{
+		Name:    "Jinzhu",
+		Age:     18,
+		Address: &pathsAddress{City: "Beijing", Zip: "200000"},
+		Orders:  []pathsOrder{{ID: 1, Status: "shipped"}, {ID: 2, Status: "paid"}},
+	}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Should only copy the paths, expected %+v, but got %+v", expected, user)
+	}
+}
+
+func TestCopyPathsWholeField(t *testing.T) {
+	var user pathsUser
+	update := pathsUpdate{Age: 20, Address: pathsAddress{City: "Beijing", Zip: "100000"}, Orders: []*pathsOrder{{ID: 1}}}
+
+	if err := CopyPaths(&user, update, []string{"Address.City", "Address", "Age", "Orders.ID"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	if user.Age != 20 || user.Address == nil || *user.Address != update.Address {
+		t.Errorf("Should copy whole fields and allocate pointers, but got %+v", user)
+	}
+	if len(user.Orders) != 1 || user.Orders[0].ID != 1 {
+		t.Errorf("Should grow slices, but got %+v", user.Orders)
+	}
+}
+
+func TestCopyPathsTagPaths(t *testing.T) {
+	type Address struct {
+		City, Zip string
+	}
+
+	type Customer struct {
+		Name    string
+		Email   string
+		Address Address
+	}
+
+	type Order struct {
+		ID       int64
+		Customer *Customer
+	}
+
+	type OrderDTO struct {
+		ID              int64
+		CustomerName    string  `copier:"Customer.Name"`
+		CustomerEmail   string  `copier:"Customer.Email"`
+		CustomerAddress Address `copier:"Customer.Address"`
+	}
+
+	update := OrderDTO{ID: 2, CustomerName: "jinzhu", CustomerEmail: "jinzhu@example.org", CustomerAddress: Address{City: "Beijing", Zip: "100000"}}
+
+	order := Order{ID: 1}
+	if err := CopyPaths(&order, &update, []string{"Customer.Name", "Customer.Address.City"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	expected := Order{ID: 1, Customer: &Customer{Name: "jinzhu", Address: Address{City: "Beijing"}}}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Should copy the paths of tagged fields, expected %+v, but got %+v", expected, order)
+	}
+
+	order = Order{ID: 1}
+	if err := CopyPaths(&order, &update, []string{"Customer"}); err != nil {
+		t.Fatalf("Should not raise error, but got %v", err)
+	}
+	expected = Order{ID: 1, Customer: &Customer{Name: "jinzhu", Email: "jinzhu@example.org", Address: update.CustomerAddress}}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Should copy tagged fields below whole paths, expected %+v, but got %+v", expected, order)
+	}
+}
+
+func TestCopyPathsOnField(t *testing.T) {
+	user := pathsUser{Orders: []pathsOrder{{}}}
+	update := pathsUpdate{Orders: []*pathsOrder{{Status: "paid"}}}
+
+	var paths []string
+	err := CopyPathsWithOption(&user, &update, []string{"Orders.Status"}, Option{
+		OnField: func(path string, from, to reflect.Value, oldTo interface{}) { paths = append(paths, path) },
+	})
+	if err != nil || !reflect.DeepEqual(paths, []string{"Orders[0].Status"}) {
+		t.Errorf("Should report the written paths, but got %v, %v", paths, err)
+	}
+}
+
+func TestCopyPathsUnknown(t *testing.T) {
+	user := pathsUser{Name: "jinzhu"}
+	err := CopyPaths(&user, &pathsUpdate{Name: "Jinzhu"}, []string{"Name", "Adress.City", "Orders.Stat", "Age.Value"})
+	if err == nil || err.Error() != "copy paths to copier.pathsUser: unknown paths Adress.City (did you mean Address?), Orders.Stat (did you mean Orders.Status?), Age.Value" {
+		t.Errorf("Should return the unknown paths, but got %v", err)
+	}
+	if user.Name != "jinzhu" {
+		t.Errorf("Should not copy with unknown paths, but got %v", user.Name)
+	}
+
+	if err := CopyPaths(user, &pathsUpdate{}, []string{"Name"}); err == nil {
+		t.Errorf("Should not copy to a value")
+	}
+}